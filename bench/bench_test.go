@@ -14,7 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/afs"
-	_ "github.com/viant/dyndb"
+	"github.com/viant/dyndb"
 	"io/ioutil"
 	"os"
 	"strconv"
@@ -104,7 +104,7 @@ func BenchmarkDatabaseSQL_QueryAll(b *testing.B) {
 	db, err := sql.Open("dynamodb", dsn)
 	//db, err := sql.Open("dynamodb", "dynamodb://localhost:8000/us-west-1?cred=aws-e2e")
 	if err != nil {
-		b.Skipf("failed to connect to db %w", err)
+		b.Skipf("failed to connect to db %v", err)
 	}
 	if !assert.Nil(b, err) {
 		return
@@ -138,7 +138,7 @@ func BenchmarkDatabaseSQL_QuerySingle(b *testing.B) {
 	db, err := sql.Open("dynamodb", dsn)
 	//db, err := sql.Open("dynamodb", "dynamodb://localhost:8000/us-west-1?cred=aws-e2e")
 	if err != nil {
-		b.Skipf("failed to connect to db %w", err)
+		b.Skipf("failed to connect to db %v", err)
 	}
 	if !assert.Nil(b, err) {
 		return
@@ -167,6 +167,23 @@ func BenchmarkDatabaseSQL_QuerySingle(b *testing.B) {
 	}
 }
 
+func BenchmarkDatabaseSQL_QueryStructs(b *testing.B) {
+	dsn := os.Getenv("TEST_DSN")
+	db, err := sql.Open("dynamodb", dsn)
+	if err != nil {
+		b.Skipf("failed to connect to db %v", err)
+	}
+	b.ReportAllocs()
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		demos, err := dyndb.Query[Demo](ctx, db, `SELECT id, state, gender, year, name, number AS n FROM usa_names`)
+		if !assert.Nil(b, err) {
+			continue
+		}
+		assert.Equal(b, 1000, len(demos))
+	}
+}
+
 func BenchmarkAwsSDK_QueryAll(b *testing.B) {
 
 	client := createLocalClient()
@@ -0,0 +1,467 @@
+package dyndb
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strings"
+	"sync"
+	"time"
+)
+
+const bulkBatchSize = 25
+
+//Bulk write operations
+const (
+	BulkPut    = "PUT"
+	BulkDelete = "DELETE"
+)
+
+type (
+	//BulkWriter writes and deletes items with concurrent BatchWriteItem requests, unprocessed items are resubmitted with backoff,
+	//Put, Delete, Flush and Close are meant to be called from a single goroutine
+	BulkWriter struct {
+		client      *dynamodb.Client
+		table       string
+		keys        []string
+		concurrency int
+		maxAttempts int
+		backoff     *retry.ExponentialJitterBackoff
+		onProgress  func(progress BulkProgress)
+		onError     func(err *BulkItemError)
+		consumed    *ConsumedCapacity
+		collectors  capacities
+		batch       []*bulkItem
+		batches     chan []*bulkItem
+		ctx         context.Context
+		cancel      context.CancelFunc
+		wg          sync.WaitGroup
+		mux         sync.Mutex
+		notify      sync.Mutex //serializes callbacks
+		progress    BulkProgress
+		seq         int64
+		err         error
+		started     bool
+		closed      bool
+	}
+
+	//BulkOption represents bulk writer option
+	BulkOption func(w *BulkWriter)
+
+	//BulkProgress represents bulk writer progress
+	BulkProgress struct {
+		//Submitted is the number of items passed to Put or Delete
+		Submitted int64
+		//Written is the number of put items
+		Written int64
+		//Deleted is the number of deleted items
+		Deleted int64
+		//Failed is the number of items that could not be written or deleted
+		Failed int64
+		//Resubmitted is the number of resubmitted unprocessed items
+		Resubmitted int64
+	}
+
+	//BulkItemError represents item that could not be written or deleted
+	BulkItemError struct {
+		//Seq is 1 based order in which the item was passed to Put or Delete
+		Seq int64
+		//Op is BulkPut or BulkDelete
+		Op string
+		//Item is put item or deleted key
+		Item map[string]types.AttributeValue
+		Err  error
+	}
+
+	bulkItem struct {
+		seq     int64
+		key     string
+		request types.WriteRequest
+	}
+)
+
+//WithConcurrency returns option setting number of concurrent BatchWriteItem requests
+func WithConcurrency(concurrency int) BulkOption {
+	return func(w *BulkWriter) {
+		if concurrency > 0 {
+			w.concurrency = concurrency
+		}
+	}
+}
+
+//WithUnprocessedRetry returns option setting max attempts and max backoff for unprocessed items
+func WithUnprocessedRetry(maxAttempts int, maxBackoff time.Duration) BulkOption {
+	return func(w *BulkWriter) {
+		if maxAttempts > 0 {
+			w.maxAttempts = maxAttempts
+		}
+		if maxBackoff > 0 {
+			w.backoff = retry.NewExponentialJitterBackoff(maxBackoff)
+		}
+	}
+}
+
+//WithProgress returns option registering progress callback, it is called after each batch
+func WithProgress(fn func(progress BulkProgress)) BulkOption {
+	return func(w *BulkWriter) {
+		w.onProgress = fn
+	}
+}
+
+//WithItemError returns option registering item error callback
+func WithItemError(fn func(err *BulkItemError)) BulkOption {
+	return func(w *BulkWriter) {
+		w.onError = fn
+	}
+}
+
+//Error returns error message
+func (e *BulkItemError) Error() string {
+	return fmt.Sprintf("%v item %v: %v", strings.ToLower(e.Op), e.Seq, e.Err)
+}
+
+//Unwrap returns underlying error
+func (e *BulkItemError) Unwrap() error {
+	return e.Err
+}
+
+//BulkWriter creates bulk writer for the table, use it with sql.Conn.Raw
+func (c *Connection) BulkWriter(ctx context.Context, table string, options ...BulkOption) (*BulkWriter, error) {
+	desc, err := c.schemas.describe(ctx, c.client, table)
+	if err != nil {
+		return nil, err
+	}
+	return newBulkWriter(ctx, c.client, desc, capacities{c.stats}, options...), nil
+}
+
+func newBulkWriter(ctx context.Context, client *dynamodb.Client, desc *types.TableDescription, collectors capacities, options ...BulkOption) *BulkWriter {
+	result := &BulkWriter{client: client, table: *desc.TableName, concurrency: 4, maxAttempts: 8,
+		backoff: retry.NewExponentialJitterBackoff(5 * time.Second), consumed: NewConsumedCapacity()}
+	for _, key := range desc.KeySchema {
+		result.keys = append(result.keys, *key.AttributeName)
+	}
+	result.collectors = append(capacities{result.consumed}, collectors...)
+	for _, option := range options {
+		option(result)
+	}
+	result.ctx, result.cancel = context.WithCancel(ctx)
+	result.batches = make(chan []*bulkItem, result.concurrency)
+	return result
+}
+
+//Put writes items, item can be map[string]types.AttributeValue, map or struct (dynamodbav tags are used)
+func (w *BulkWriter) Put(ctx context.Context, items ...interface{}) error {
+	for _, item := range items {
+		value, err := marshalItem(item)
+		if err != nil {
+			return err
+		}
+		if err = w.add(ctx, types.WriteRequest{PutRequest: &types.PutRequest{Item: value}}, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Delete deletes items by keys, key can be item or key map[string]types.AttributeValue, map or struct, non key attributes are ignored
+func (w *BulkWriter) Delete(ctx context.Context, keys ...interface{}) error {
+	for _, key := range keys {
+		value, err := marshalItem(key)
+		if err != nil {
+			return err
+		}
+		itemKey := make(map[string]types.AttributeValue, len(w.keys))
+		for _, name := range w.keys {
+			attr, ok := value[name]
+			if !ok {
+				return fmt.Errorf("%w: key attribute %v was missing", ErrValidation, name)
+			}
+			itemKey[name] = attr
+		}
+		if err = w.add(ctx, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: itemKey}}, itemKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Flush submits buffered items without waiting for completion
+func (w *BulkWriter) Flush(ctx context.Context) error {
+	if len(w.batch) == 0 {
+		return w.Err()
+	}
+	batch := w.batch
+	w.batch = nil
+	return w.submit(ctx, batch)
+}
+
+//Close submits buffered items and waits for all batches, it returns the first non item error
+func (w *BulkWriter) Close(ctx context.Context) error {
+	if w.closed {
+		return w.Err()
+	}
+	err := w.Flush(ctx)
+	w.closed = true
+	close(w.batches)
+	w.wg.Wait()
+	w.cancel()
+	if err != nil {
+		return err
+	}
+	return w.Err()
+}
+
+//Progress returns current progress
+func (w *BulkWriter) Progress() BulkProgress {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.progress
+}
+
+//ConsumedCapacity returns capacity consumed by the writer
+func (w *BulkWriter) ConsumedCapacity() *ConsumedCapacity {
+	return w.consumed.Snapshot()
+}
+
+//Err returns the first non item error, it stops the writer
+func (w *BulkWriter) Err() error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.err
+}
+
+func (w *BulkWriter) add(ctx context.Context, request types.WriteRequest, item map[string]types.AttributeValue) error {
+	if w.closed {
+		return fmt.Errorf("%w: bulk writer was closed", ErrValidation)
+	}
+	w.seq++
+	w.mux.Lock()
+	w.progress.Submitted++
+	w.mux.Unlock()
+	if w.batch = append(w.batch, &bulkItem{seq: w.seq, key: w.itemKey(item), request: request}); len(w.batch) < bulkBatchSize {
+		return nil
+	}
+	return w.Flush(ctx)
+}
+
+func (w *BulkWriter) submit(ctx context.Context, batch []*bulkItem) error {
+	if err := w.Err(); err != nil {
+		return err
+	}
+	if !w.started {
+		w.started = true
+		for i := 0; i < w.concurrency; i++ {
+			w.wg.Add(1)
+			go w.run()
+		}
+	}
+	select {
+	case w.batches <- batch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.ctx.Done():
+		if err := w.Err(); err != nil {
+			return err
+		}
+		return w.ctx.Err()
+	}
+}
+
+func (w *BulkWriter) run() {
+	defer w.wg.Done()
+	for batch := range w.batches {
+		if err := w.ctx.Err(); err != nil {
+			w.fail(batch, err)
+			continue
+		}
+		if err := w.write(batch); err != nil {
+			w.mux.Lock()
+			if w.err == nil {
+				w.err = err
+			}
+			w.mux.Unlock()
+			w.cancel()
+		}
+	}
+}
+
+//write writes batch, rejected batch (i.e. duplicated keys) is written item by item to report item errors
+func (w *BulkWriter) write(batch []*bulkItem) error {
+	pending := make(map[string]*bulkItem, len(batch))
+	for _, item := range batch {
+		pending[item.key] = item
+	}
+	remaining := batch
+	for attempt := 0; len(remaining) > 0 && attempt < w.maxAttempts; attempt++ {
+		if attempt > 0 {
+			w.update(func(progress *BulkProgress) { progress.Resubmitted += int64(len(remaining)) })
+			delay, _ := w.backoff.BackoffDelay(attempt, nil)
+			select {
+			case <-w.ctx.Done():
+				w.done(batch, remaining, w.ctx.Err())
+				return w.ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		requests := make([]types.WriteRequest, 0, len(remaining))
+		for _, item := range remaining {
+			requests = append(requests, item.request)
+		}
+		output, err := w.client.BatchWriteItem(w.ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems:           map[string][]types.WriteRequest{w.table: requests},
+			ReturnConsumedCapacity: types.ReturnConsumedCapacityIndexes,
+		})
+		if err != nil {
+			if kind, _ := classify(err); kind != ErrValidation {
+				w.done(batch, remaining, err)
+				return err
+			}
+			if processed := exclude(batch, remaining); len(processed) > 0 {
+				w.done(processed, nil, nil)
+			}
+			return w.writeItems(remaining)
+		}
+		for i := range output.ConsumedCapacity {
+			w.collectors.add(&output.ConsumedCapacity[i], true)
+		}
+		remaining = remaining[:0:0]
+		for _, request := range output.UnprocessedItems[w.table] {
+			if item, ok := pending[w.itemKey(requestItem(request))]; ok {
+				remaining = append(remaining, item)
+			}
+		}
+	}
+	w.done(batch, remaining, fmt.Errorf("%w: unprocessed after %v attempts", ErrThrottled, w.maxAttempts))
+	return nil
+}
+
+//writeItems writes items one by one
+func (w *BulkWriter) writeItems(batch []*bulkItem) error {
+	for i, item := range batch {
+		var consumed *types.ConsumedCapacity
+		var err error
+		if put := item.request.PutRequest; put != nil {
+			var output *dynamodb.PutItemOutput
+			if output, err = w.client.PutItem(w.ctx, &dynamodb.PutItemInput{TableName: &w.table, Item: put.Item, ReturnConsumedCapacity: types.ReturnConsumedCapacityIndexes}); err == nil {
+				consumed = output.ConsumedCapacity
+			}
+		} else {
+			var output *dynamodb.DeleteItemOutput
+			if output, err = w.client.DeleteItem(w.ctx, &dynamodb.DeleteItemInput{TableName: &w.table, Key: item.request.DeleteRequest.Key, ReturnConsumedCapacity: types.ReturnConsumedCapacityIndexes}); err == nil {
+				consumed = output.ConsumedCapacity
+			}
+		}
+		if err != nil {
+			if kind, _ := classify(err); kind != ErrValidation {
+				w.fail(batch[i:], err)
+				return err
+			}
+			w.fail(batch[i:i+1], err)
+			continue
+		}
+		w.collectors.add(consumed, true)
+		w.done(batch[i:i+1], nil, nil)
+	}
+	return nil
+}
+
+//exclude returns batch items other than supplied ones
+func exclude(batch, items []*bulkItem) []*bulkItem {
+	index := make(map[int64]bool, len(items))
+	for _, item := range items {
+		index[item.seq] = true
+	}
+	var result []*bulkItem
+	for _, item := range batch {
+		if !index[item.seq] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+//fail reports all batch items as failed
+func (w *BulkWriter) fail(batch []*bulkItem, err error) {
+	w.done(batch, batch, err)
+}
+
+//done updates progress and notifies callbacks, callbacks are called serially outside of the writer lock
+func (w *BulkWriter) done(batch []*bulkItem, failed []*bulkItem, err error) {
+	w.notify.Lock()
+	defer w.notify.Unlock()
+	w.mux.Lock()
+	index := make(map[int64]bool, len(failed))
+	var itemErrors []*BulkItemError
+	for _, item := range failed {
+		index[item.seq] = true
+		w.progress.Failed++
+		if w.onError != nil {
+			op, value := BulkPut, requestItem(item.request)
+			if item.request.DeleteRequest != nil {
+				op = BulkDelete
+			}
+			itemErrors = append(itemErrors, &BulkItemError{Seq: item.seq, Op: op, Item: value, Err: err})
+		}
+	}
+	for _, item := range batch {
+		switch {
+		case index[item.seq]:
+		case item.request.PutRequest != nil:
+			w.progress.Written++
+		default:
+			w.progress.Deleted++
+		}
+	}
+	progress := w.progress
+	w.mux.Unlock()
+	for _, itemError := range itemErrors {
+		w.onError(itemError)
+	}
+	if w.onProgress != nil {
+		w.onProgress(progress)
+	}
+}
+
+func (w *BulkWriter) update(fn func(progress *BulkProgress)) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	fn(&w.progress)
+}
+
+//itemKey returns item primary key text
+func (w *BulkWriter) itemKey(item map[string]types.AttributeValue) string {
+	var result []string
+	for _, name := range w.keys {
+		switch actual := item[name].(type) {
+		case *types.AttributeValueMemberS:
+			result = append(result, actual.Value)
+		case *types.AttributeValueMemberN:
+			result = append(result, actual.Value)
+		case *types.AttributeValueMemberB:
+			result = append(result, string(actual.Value))
+		}
+	}
+	return strings.Join(result, "\x00")
+}
+
+func requestItem(request types.WriteRequest) map[string]types.AttributeValue {
+	if request.PutRequest != nil {
+		return request.PutRequest.Item
+	}
+	return request.DeleteRequest.Key
+}
+
+func marshalItem(item interface{}) (map[string]types.AttributeValue, error) {
+	if actual, ok := item.(map[string]types.AttributeValue); ok {
+		return actual, nil
+	}
+	result, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package dyndb
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBulkWriter(t *testing.T) {
+	type user struct {
+		Id   int
+		Name string
+	}
+	var testCases = []struct {
+		description string
+		unprocessed int //number of items reported unprocessed by BatchWriteItem call
+		persistent  bool
+		maxAttempts int
+		puts        int
+		deletes     int
+		expect      BulkProgress
+		failed      int
+	}{
+		{
+			description: "unprocessed items resubmitted",
+			unprocessed: 1,
+			maxAttempts: 3,
+			puts:        30,
+			deletes:     1,
+			expect:      BulkProgress{Submitted: 31, Written: 30, Deleted: 1, Resubmitted: 2},
+		},
+		{
+			description: "unprocessed items reported",
+			unprocessed: 25,
+			persistent:  true,
+			maxAttempts: 2,
+			puts:        3,
+			expect:      BulkProgress{Submitted: 3, Failed: 3, Resubmitted: 3},
+			failed:      3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		unprocessed, persistent := testCase.unprocessed, testCase.persistent
+		server := newTestServer(map[string]testHandler{"BatchWriteItem": func(input []byte) (string, error) {
+			request := struct {
+				RequestItems map[string][]json.RawMessage
+			}{}
+			_ = json.Unmarshal(input, &request)
+			requests := request.RequestItems["Users"]
+			switch {
+			case len(requests) > unprocessed:
+				requests = requests[:unprocessed]
+			case !persistent:
+				requests = nil
+			}
+			output := map[string]interface{}{
+				"ConsumedCapacity": []map[string]interface{}{{"TableName": "Users", "CapacityUnits": 1}},
+				"UnprocessedItems": map[string]interface{}{},
+			}
+			if len(requests) > 0 {
+				output["UnprocessedItems"] = map[string]interface{}{"Users": requests}
+			}
+			data, err := json.Marshal(output)
+			return string(data), err
+		}})
+		client := server.Client()
+		desc := &types.TableDescription{TableName: aws.String("Users"), KeySchema: []types.KeySchemaElement{{AttributeName: aws.String("Id"), KeyType: types.KeyTypeHash}}}
+		var failed []*BulkItemError
+		var progress []BulkProgress
+		var mux sync.Mutex
+		ctx := context.Background()
+		var writer *BulkWriter
+		//callbacks read writer state, so that they would deadlock if called with writer lock held
+		writer = newBulkWriter(ctx, client, desc, nil, WithConcurrency(2), WithUnprocessedRetry(testCase.maxAttempts, time.Millisecond),
+			WithItemError(func(err *BulkItemError) {
+				mux.Lock()
+				defer mux.Unlock()
+				assert.Nil(t, writer.Err(), testCase.description)
+				failed = append(failed, err)
+			}),
+			WithProgress(func(snapshot BulkProgress) {
+				mux.Lock()
+				defer mux.Unlock()
+				current := writer.Progress()
+				assert.True(t, current.Written+current.Deleted+current.Failed >= snapshot.Written+snapshot.Deleted+snapshot.Failed, testCase.description)
+				progress = append(progress, snapshot)
+			}))
+		for i := 0; i < testCase.puts; i++ {
+			assert.Nil(t, writer.Put(ctx, &user{Id: i, Name: "user"}), testCase.description)
+		}
+		for i := 0; i < testCase.deletes; i++ {
+			assert.Nil(t, writer.Delete(ctx, map[string]interface{}{"Id": i, "Name": "ignored"}), testCase.description)
+		}
+		assert.Nil(t, writer.Close(ctx), testCase.description)
+		assert.EqualValues(t, testCase.expect, writer.Progress(), testCase.description)
+		assert.EqualValues(t, testCase.failed, len(failed), testCase.description)
+		if assert.True(t, len(progress) > 0, testCase.description) {
+			assert.EqualValues(t, testCase.expect, progress[len(progress)-1], testCase.description)
+		}
+		assert.True(t, writer.ConsumedCapacity().Requests > 0, testCase.description)
+		server.Close()
+	}
+}
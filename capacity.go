@@ -0,0 +1,152 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"sync"
+)
+
+const consumedCapacityKey = contextKey("consumedCapacity")
+
+//Capacity represents consumed capacity units
+type Capacity struct {
+	CapacityUnits      float64
+	ReadCapacityUnits  float64
+	WriteCapacityUnits float64
+}
+
+//ConsumedCapacity represents cumulative consumed capacity with per table and per index breakdown
+type ConsumedCapacity struct {
+	Capacity
+	//Requests is the number of DynamoDB requests reporting consumed capacity
+	Requests int
+	//Tables holds table capacity keyed by table name
+	Tables map[string]*Capacity
+	//Indexes holds index capacity keyed by table.index
+	Indexes map[string]*Capacity
+	mux     sync.Mutex
+}
+
+//Result represents statement result with consumed capacity
+type Result interface {
+	driver.Result
+	//ConsumedCapacity returns capacity consumed by the statement
+	ConsumedCapacity() *ConsumedCapacity
+}
+
+//WithConsumedCapacity returns context collecting capacity consumed by statements executed with it
+func WithConsumedCapacity(ctx context.Context, collector *ConsumedCapacity) context.Context {
+	return context.WithValue(ctx, consumedCapacityKey, collector)
+}
+
+//NewConsumedCapacity creates consumed capacity
+func NewConsumedCapacity() *ConsumedCapacity {
+	return &ConsumedCapacity{Tables: map[string]*Capacity{}, Indexes: map[string]*Capacity{}}
+}
+
+//Snapshot returns a copy of consumed capacity
+func (c *ConsumedCapacity) Snapshot() *ConsumedCapacity {
+	result := NewConsumedCapacity()
+	if c == nil {
+		return result
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	result.Capacity = c.Capacity
+	result.Requests = c.Requests
+	for k, v := range c.Tables {
+		capacity := *v
+		result.Tables[k] = &capacity
+	}
+	for k, v := range c.Indexes {
+		capacity := *v
+		result.Indexes[k] = &capacity
+	}
+	return result
+}
+
+//Reset resets consumed capacity
+func (c *ConsumedCapacity) Reset() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.Capacity = Capacity{}
+	c.Requests = 0
+	c.Tables = map[string]*Capacity{}
+	c.Indexes = map[string]*Capacity{}
+}
+
+//add adds DynamoDB reported consumed capacity, capacity units without read/write breakdown are attributed by operation
+func (c *ConsumedCapacity) add(consumed *types.ConsumedCapacity, write bool) {
+	if c == nil || consumed == nil {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.Requests++
+	c.Capacity.add(consumed.CapacityUnits, consumed.ReadCapacityUnits, consumed.WriteCapacityUnits, write)
+	table := ""
+	if consumed.TableName != nil {
+		table = *consumed.TableName
+	}
+	if c.Tables == nil {
+		c.Tables = map[string]*Capacity{}
+	}
+	if c.Indexes == nil {
+		c.Indexes = map[string]*Capacity{}
+	}
+	tableCapacity := lookupCapacity(c.Tables, table)
+	if consumed.Table != nil {
+		tableCapacity.addCapacity(consumed.Table, write)
+	} else if len(consumed.GlobalSecondaryIndexes) == 0 && len(consumed.LocalSecondaryIndexes) == 0 {
+		tableCapacity.add(consumed.CapacityUnits, consumed.ReadCapacityUnits, consumed.WriteCapacityUnits, write)
+	}
+	for _, indexes := range []map[string]types.Capacity{consumed.GlobalSecondaryIndexes, consumed.LocalSecondaryIndexes} {
+		for name, capacity := range indexes {
+			lookupCapacity(c.Indexes, table+"."+name).addCapacity(&capacity, write)
+		}
+	}
+}
+
+func lookupCapacity(index map[string]*Capacity, key string) *Capacity {
+	result, ok := index[key]
+	if !ok {
+		result = &Capacity{}
+		index[key] = result
+	}
+	return result
+}
+
+func (c *Capacity) addCapacity(capacity *types.Capacity, write bool) {
+	c.add(capacity.CapacityUnits, capacity.ReadCapacityUnits, capacity.WriteCapacityUnits, write)
+}
+
+func (c *Capacity) add(units, readUnits, writeUnits *float64, write bool) {
+	if units == nil {
+		return
+	}
+	c.CapacityUnits += *units
+	if readUnits == nil && writeUnits == nil {
+		if write {
+			c.WriteCapacityUnits += *units
+		} else {
+			c.ReadCapacityUnits += *units
+		}
+		return
+	}
+	if readUnits != nil {
+		c.ReadCapacityUnits += *readUnits
+	}
+	if writeUnits != nil {
+		c.WriteCapacityUnits += *writeUnits
+	}
+}
+
+//capacities represents consumed capacity collectors
+type capacities []*ConsumedCapacity
+
+func (c capacities) add(consumed *types.ConsumedCapacity, write bool) {
+	for _, collector := range c {
+		collector.add(consumed, write)
+	}
+}
@@ -0,0 +1,77 @@
+package dyndb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConsumedCapacity_Add(t *testing.T) {
+	var testCases = []struct {
+		description string
+		consumed    []*types.ConsumedCapacity
+		write       bool
+		expect      Capacity
+		tables      map[string]Capacity
+		indexes     map[string]Capacity
+	}{
+		{
+			description: "read pages",
+			consumed: []*types.ConsumedCapacity{
+				{TableName: aws.String("Users"), CapacityUnits: aws.Float64(1)},
+				{TableName: aws.String("Users"), CapacityUnits: aws.Float64(0.5)},
+			},
+			expect: Capacity{CapacityUnits: 1.5, ReadCapacityUnits: 1.5},
+			tables: map[string]Capacity{"Users": {CapacityUnits: 1.5, ReadCapacityUnits: 1.5}},
+		},
+		{
+			description: "write with index breakdown",
+			consumed: []*types.ConsumedCapacity{
+				{
+					TableName:              aws.String("Users"),
+					CapacityUnits:          aws.Float64(3),
+					Table:                  &types.Capacity{CapacityUnits: aws.Float64(2)},
+					GlobalSecondaryIndexes: map[string]types.Capacity{"ByName": {CapacityUnits: aws.Float64(1)}},
+				},
+			},
+			write:   true,
+			expect:  Capacity{CapacityUnits: 3, WriteCapacityUnits: 3},
+			tables:  map[string]Capacity{"Users": {CapacityUnits: 2, WriteCapacityUnits: 2}},
+			indexes: map[string]Capacity{"Users.ByName": {CapacityUnits: 1, WriteCapacityUnits: 1}},
+		},
+		{
+			description: "provisioned read write breakdown",
+			consumed: []*types.ConsumedCapacity{
+				{TableName: aws.String("Users"), CapacityUnits: aws.Float64(2), ReadCapacityUnits: aws.Float64(0.5), WriteCapacityUnits: aws.Float64(1.5)},
+				nil,
+			},
+			write:  true,
+			expect: Capacity{CapacityUnits: 2, ReadCapacityUnits: 0.5, WriteCapacityUnits: 1.5},
+			tables: map[string]Capacity{"Users": {CapacityUnits: 2, ReadCapacityUnits: 0.5, WriteCapacityUnits: 1.5}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		statement := NewConsumedCapacity()
+		connection := NewConsumedCapacity()
+		collectors := capacities{statement, connection}
+		for _, consumed := range testCase.consumed {
+			collectors.add(consumed, testCase.write)
+		}
+		for _, actual := range []*ConsumedCapacity{statement, connection.Snapshot()} {
+			assert.EqualValuesf(t, testCase.expect, actual.Capacity, testCase.description)
+			for name, expect := range testCase.tables {
+				if assert.NotNilf(t, actual.Tables[name], testCase.description) {
+					assert.EqualValuesf(t, expect, *actual.Tables[name], testCase.description)
+				}
+			}
+			assert.EqualValuesf(t, len(testCase.indexes), len(actual.Indexes), testCase.description)
+			for name, expect := range testCase.indexes {
+				if assert.NotNilf(t, actual.Indexes[name], testCase.description) {
+					assert.EqualValuesf(t, expect, *actual.Indexes[name], testCase.description)
+				}
+			}
+		}
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
 	"github.com/viant/dyndb/internal/exec"
 	"github.com/viant/sqlparser"
 	"strings"
@@ -14,9 +14,16 @@ import (
 
 //Connection represent connection
 type Connection struct {
-	cfg    *aws.Config
-	client *dynamodb.Client
-	executions
+	cfg            *aws.Config
+	client         *dynamodb.Client
+	streams        *dynamodbstreams.Client
+	versions       map[string]string
+	consistentRead bool
+	wildcardPages  int
+	stats          *ConsumedCapacity
+	observer       *observer
+	schemas        *schemaCache
+	executions     *executionCache
 }
 
 // Prepare returns a prepared statement, bound to this Connection.
@@ -28,10 +35,10 @@ func (c *Connection) Prepare(SQL string) (driver.Stmt, error) {
 func (c *Connection) PrepareContext(ctx context.Context, SQL string) (driver.Stmt, error) {
 	execution, err := c.getExecution(ctx, SQL)
 	if err != nil {
-		return nil, err
+		return nil, newError(err, SQL, "")
 	}
 
-	return &Statement{execution: execution, client: c.client}, err
+	return &Statement{execution: execution, client: c.client, streams: c.streams, schemas: c.schemas, consistentRead: c.consistentRead, wildcardPages: c.wildcardPages, stats: c.stats, observer: c.observer}, err
 }
 
 func sqlLowerPrefix(SQL string) string {
@@ -43,81 +50,148 @@ func sqlLowerPrefix(SQL string) string {
 }
 
 func (c *Connection) queryExecution(ctx context.Context, SQL string) (*exec.Execution, error) {
+	SQL, hints := exec.ParseHints(SQL)
 	aQuery, err := sqlparser.ParseQuery(SQL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse SQL: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse: %v", exec.ErrUnsupported, err)
 	}
 	tableName := sqlparser.TableName(aQuery)
-	desc, err := tableDescription(ctx, c.client, tableName)
+	desc, err := c.schemas.describe(ctx, c.client, tableName)
+	if err != nil {
+		return nil, err
+	}
+	execution, err := exec.NewQuery(tableName, aQuery, desc)
 	if err != nil {
 		return nil, err
 	}
-	return exec.NewQuery(tableName, aQuery, desc)
+	execution.ConsistentRead = hints.ConsistentRead
+	return execution, nil
 }
 
-func (c *Connection) insertExecution(ctx context.Context, SQL string) (*exec.Execution, error) {
+func (c *Connection) insertExecution(ctx context.Context, SQL string, replace bool) (*exec.Execution, error) {
+	if replace { //REPLACE INTO uses INSERT syntax
+		SQL = "INSERT" + strings.TrimSpace(SQL)[len("REPLACE"):]
+	}
+	SQL, returning, err := exec.ParseReturning(SQL)
+	if err != nil {
+		return nil, err
+	}
+	SQL, conflict, err := exec.ParseConflict(SQL)
+	if err != nil {
+		return nil, err
+	}
+	if replace && conflict != nil {
+		return nil, fmt.Errorf("%w: REPLACE with ON CONFLICT clause", exec.ErrUnsupported)
+	}
 	stmt, err := sqlparser.ParseInsert(SQL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse SQL: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse: %v", exec.ErrUnsupported, err)
 	}
 	tableName := sqlparser.TableName(stmt)
-	desc, err := tableDescription(ctx, c.client, tableName)
+	desc, err := c.schemas.describe(ctx, c.client, tableName)
 	if err != nil {
 		return nil, err
 	}
-	return exec.NewInsert(tableName, stmt, desc)
+	return exec.NewUpsert(tableName, stmt, returning, conflict, replace, c.versions[tableName], desc)
 }
 
 func (c *Connection) updateExecution(ctx context.Context, SQL string) (*exec.Execution, error) {
-	stmt, err := sqlparser.ParseInsert(SQL)
+	stmt, err := exec.ParseUpdate(SQL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse SQL: %w", err)
+		return nil, err
 	}
-	tableName := sqlparser.TableName(stmt)
-	desc, err := tableDescription(ctx, c.client, tableName)
+	desc, err := c.schemas.describe(ctx, c.client, stmt.Table)
 	if err != nil {
 		return nil, err
 	}
-	return exec.NewUpdate(tableName, stmt, desc)
+	stmt.Version = c.versions[stmt.Table]
+	return exec.NewUpdate(stmt.Table, stmt, desc)
 }
 
 func (c *Connection) deleteExecution(ctx context.Context, SQL string) (*exec.Execution, error) {
+	SQL, returning, err := exec.ParseReturning(SQL)
+	if err != nil {
+		return nil, err
+	}
 	stmt, err := sqlparser.ParseDelete(SQL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse SQL: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse: %v", exec.ErrUnsupported, err)
 	}
 	tableName := sqlparser.TableName(stmt)
-	desc, _ := tableDescription(ctx, c.client, tableName)
-	return exec.NewDelete(tableName, stmt, desc)
+	desc, err := c.schemas.describe(ctx, c.client, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return exec.NewDelete(tableName, stmt, returning, c.versions[tableName], desc)
 }
 
 func (c *Connection) createTableExecution(ctx context.Context, SQL string) (*exec.Execution, error) {
 	spec, err := sqlparser.ParseCreateTable(SQL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse SQL: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse: %v", exec.ErrUnsupported, err)
 	}
 	tableName := sqlparser.TableName(spec)
-	desc, _ := tableDescription(ctx, c.client, tableName)
+	desc, err := c.schemas.optionalDescribe(ctx, c.client, tableName)
+	if err != nil {
+		return nil, err
+	}
 	return exec.NewCreateTable(tableName, spec, desc)
 }
 
 func (c *Connection) dropTableExecution(ctx context.Context, SQL string) (*exec.Execution, error) {
 	spec, err := sqlparser.ParseDropTable(SQL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse SQL: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse: %v", exec.ErrUnsupported, err)
 	}
 	tableName := sqlparser.TableName(spec)
-	desc, _ := tableDescription(ctx, c.client, tableName)
+	desc, err := c.schemas.optionalDescribe(ctx, c.client, tableName)
+	if err != nil {
+		return nil, err
+	}
 	return exec.NewDropTable(tableName, spec, desc)
 }
 
-func tableDescription(ctx context.Context, client *dynamodb.Client, table string) (*types.TableDescription, error) {
-	var desc *types.TableDescription
-	describeOutput, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: &table})
-	if describeOutput != nil {
-		desc = describeOutput.Table
+func (c *Connection) explainExecution(ctx context.Context, SQL string) (*exec.Execution, error) {
+	target, err := c.getExecution(ctx, strings.TrimSpace(SQL)[len("explain"):])
+	if err != nil {
+		return nil, err
+	}
+	desc, err := c.schemas.describe(ctx, c.client, target.Table)
+	if err != nil {
+		return nil, err
+	}
+	return exec.NewExplain(target, desc)
+}
+
+func (c *Connection) copyExecution(ctx context.Context, SQL string) (*exec.Execution, error) {
+	kind, table, aCopy, err := exec.ParseCopy(SQL)
+	if err != nil {
+		return nil, err
+	}
+	if kind == exec.KindCopyTo {
+		if aCopy.Query, err = c.getExecution(ctx, aCopy.QuerySQL); err != nil {
+			return nil, err
+		}
 	}
-	return desc, err
+	return exec.NewCopy(kind, table, aCopy)
+}
+
+func (c *Connection) metaExecution(SQL string) (*exec.Execution, error) {
+	kind, table, err := exec.ParseMeta(SQL)
+	if err != nil {
+		return nil, err
+	}
+	return exec.NewMeta(kind, table)
+}
+
+//ConsumedCapacity returns cumulative capacity consumed by statements executed on this connection
+func (c *Connection) ConsumedCapacity() *ConsumedCapacity {
+	return c.stats.Snapshot()
+}
+
+//ExecutionCacheStats returns stats of execution cache shared by all connections of this connection DSN
+func (c *Connection) ExecutionCacheStats() CacheStats {
+	return c.executions.Stats()
 }
 
 //Ping pings server
@@ -150,29 +224,47 @@ func (c *Connection) getExecution(ctx context.Context, SQL string) (*exec.Execut
 	if execution != nil {
 		return execution, nil
 	}
-	var err error
+	original := SQL
+	SQL, names, err := exec.ParseNamed(SQL)
+	if err != nil {
+		return nil, err
+	}
 	SQLType := sqlLowerPrefix(SQL)
-	if strings.HasPrefix(SQLType, "select") {
-		execution, err = c.queryExecution(ctx, SQL)
+	parsable := exec.EscapeIndexes(SQL)
+	if strings.HasPrefix(SQLType, "select") && exec.IsSchemaQuery(SQL) {
+		execution, err = exec.NewSchemaQuery(SQL, c.cfg.Region)
+	} else if strings.HasPrefix(SQLType, "select") && exec.IsStreamQuery(SQL) {
+		execution, err = exec.NewStreamQuery(SQL)
+	} else if strings.HasPrefix(SQLType, "select") {
+		execution, err = c.queryExecution(ctx, parsable)
 	} else if strings.HasPrefix(SQLType, "insert") {
-		return c.insertExecution(ctx, SQL)
+		execution, err = c.insertExecution(ctx, parsable, false)
+	} else if strings.HasPrefix(SQLType, "replace") {
+		execution, err = c.insertExecution(ctx, parsable, true)
 	} else if strings.HasPrefix(SQLType, "update") {
-		return c.updateExecution(ctx, SQL)
+		execution, err = c.updateExecution(ctx, parsable)
 	} else if strings.HasPrefix(SQLType, "delete") {
-		return c.deleteExecution(ctx, SQL)
+		execution, err = c.deleteExecution(ctx, parsable)
 	} else if strings.HasPrefix(SQLType, "create") {
-		return c.createTableExecution(ctx, SQL)
+		execution, err = c.createTableExecution(ctx, parsable)
 	} else if strings.HasPrefix(SQLType, "drop") {
-		return c.dropTableExecution(ctx, SQL)
+		execution, err = c.dropTableExecution(ctx, parsable)
+	} else if strings.HasPrefix(SQLType, "explain") {
+		execution, err = c.explainExecution(ctx, SQL)
+	} else if strings.HasPrefix(SQLType, "show") || strings.HasPrefix(SQLType, "desc") {
+		execution, err = c.metaExecution(SQL)
+	} else if strings.HasPrefix(SQLType, "copy") {
+		execution, err = c.copyExecution(ctx, SQL)
 	} else {
-		return nil, fmt.Errorf("unuspported query: %v", SQL)
+		return nil, fmt.Errorf("%w: %v", exec.ErrUnsupported, SQL)
 	}
 	if err != nil {
 		return nil, err
 	}
-	execution.SQL = SQL
-	c.executions.Put(execution)
-	return execution, err
+	execution.SQL = original
+	execution.Names = names
+	c.executions.Put(execution, c.schemas.expiry(execution.Table))
+	return execution, nil
 }
 
 //IsValid check is Connection is valid
@@ -0,0 +1,85 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	aws2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/viant/scy/auth/aws"
+	"time"
+)
+
+//Connector represents database/sql driver connector, use it with sql.OpenDB to register observers
+type Connector struct {
+	cfg      *Config
+	dsn      string
+	observer *observer
+	retryer  aws2.Retryer //shared by all connector connections, thus they share one retry token budget
+}
+
+//Option represents connector option
+type Option func(c *Connector)
+
+//WithObserver returns option registering statement execution observers
+func WithObserver(observers ...Observer) Option {
+	return func(c *Connector) {
+		c.observer.observers = append(c.observer.observers, observers...)
+	}
+}
+
+//WithSlowThreshold returns option marking statements and pages taking at least threshold as slow
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(c *Connector) {
+		c.observer.slowThreshold = threshold
+	}
+}
+
+//NewConnector creates a connector
+func NewConnector(dsn string, options ...Option) (*Connector, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("dynamodb dsn was empty")
+	}
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	result := &Connector{cfg: cfg, dsn: dsn, observer: &observer{slowThreshold: cfg.SlowThreshold}, retryer: newRetryer(cfg)}
+	for _, option := range options {
+		option(result)
+	}
+	return result, nil
+}
+
+//Connect returns a new connection
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cfg := c.cfg
+	awsConfig, err := aws.NewConfig(ctx, &cfg.Aws)
+	if err != nil {
+		return nil, err
+	}
+	return &Connection{
+		cfg: awsConfig,
+		client: dynamodb.NewFromConfig(*awsConfig, func(options *dynamodb.Options) {
+			options.DefaultsMode = aws2.DefaultsModeLegacy
+			options.Retryer = c.retryer
+		}),
+		streams: dynamodbstreams.NewFromConfig(*awsConfig, func(options *dynamodbstreams.Options) {
+			options.DefaultsMode = aws2.DefaultsModeLegacy
+			options.Retryer = c.retryer
+		}),
+		executions:     sharedExecutionCache(c.dsn, cfg.ExecMaxCache),
+		versions:       cfg.Versions,
+		consistentRead: cfg.ConsistentRead,
+		wildcardPages:  cfg.WildcardPages,
+		stats:          NewConsumedCapacity(),
+		observer:       c.observer,
+		schemas:        &schemaCache{scope: c.dsn, ttl: cfg.SchemaTTL},
+	}, nil
+}
+
+//Driver returns driver
+func (c *Connector) Driver() driver.Driver {
+	return &Driver{}
+}
@@ -0,0 +1,20 @@
+package dyndb
+
+import "context"
+
+type contextKey string
+
+const consistentReadKey = contextKey("consistentRead")
+
+// WithConsistentRead returns context requesting strongly consistent (or eventually consistent when false) SELECT reads
+func WithConsistentRead(ctx context.Context, consistent bool) context.Context {
+	return context.WithValue(ctx, consistentReadKey, consistent)
+}
+
+const prefetchKey = contextKey("prefetch")
+
+// WithPrefetch returns context requesting SELECT rows to fetch up to pages next pages in background while rows are read,
+// prefetch stops when rows are closed or the context is cancelled
+func WithPrefetch(ctx context.Context, pages int) context.Context {
+	return context.WithValue(ctx, prefetchKey, pages)
+}
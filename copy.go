@@ -0,0 +1,186 @@
+package dyndb
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/dyndb/internal/exec"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//CopyError represents COPY FROM rows that could not be decoded or written, other rows are written
+type CopyError struct {
+	//Written is the number of written rows
+	Written int64
+	//Rows holds failed rows
+	Rows []*RowError
+}
+
+//RowError represents COPY FROM row error
+type RowError struct {
+	//Row is 1 based record ordinal (line number for JSON Lines)
+	Row int
+	Err error
+}
+
+//Error returns error message
+func (e *CopyError) Error() string {
+	return fmt.Sprintf("failed to copy %v row(s), written: %v, first: %v", len(e.Rows), e.Written, e.Rows[0])
+}
+
+//Error returns error message
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %v: %v", e.Row, e.Err)
+}
+
+//Unwrap returns underlying error
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+//copyFrom imports JSON Lines or CSV rows with bulk writer
+func (s *Statement) copyFrom(ctx context.Context, collectors capacities) (driver.Result, error) {
+	execution := s.execution
+	desc, err := s.schemas.describe(ctx, s.client, execution.Table)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := openCopyReader(ctx, execution.Copy.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	rows, err := execution.Copy.NewReader(reader, desc)
+	if err != nil {
+		return nil, err
+	}
+	copyErr := &CopyError{}
+	var submitted []int //row by bulk item seq
+	var mux sync.Mutex
+	writer := newBulkWriter(ctx, s.client, desc, collectors, WithItemError(func(err *BulkItemError) {
+		mux.Lock()
+		defer mux.Unlock()
+		copyErr.Rows = append(copyErr.Rows, &RowError{Row: submitted[err.Seq-1], Err: err.Err})
+	}))
+	for {
+		row, err := rows.Read()
+		if err == io.EOF {
+			break
+		}
+		if err == nil && row.Err != nil {
+			mux.Lock()
+			copyErr.Rows = append(copyErr.Rows, &RowError{Row: row.Row, Err: row.Err})
+			mux.Unlock()
+			continue
+		}
+		if err == nil {
+			mux.Lock()
+			submitted = append(submitted, row.Row)
+			mux.Unlock()
+			err = writer.Put(ctx, row.Item)
+		}
+		if err != nil {
+			writer.Close(ctx)
+			return nil, err
+		}
+	}
+	if err = writer.Close(ctx); err != nil {
+		return nil, err
+	}
+	copyErr.Written = writer.Progress().Written
+	if len(copyErr.Rows) > 0 {
+		sort.Slice(copyErr.Rows, func(i, j int) bool {
+			return copyErr.Rows[i].Row < copyErr.Rows[j].Row
+		})
+		return nil, copyErr
+	}
+	return &result{totalRows: copyErr.Written, consumed: collectors[0]}, nil
+}
+
+//copyTo exports query rows
+func (s *Statement) copyTo(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	aCopy := s.execution.Copy
+	query := &Statement{execution: aCopy.Query, client: s.client, streams: s.streams, schemas: s.schemas, consistentRead: s.consistentRead, wildcardPages: s.wildcardPages, stats: s.stats, observer: s.observer}
+	rows, err := query.queryContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	writer, err := afs.New().NewWriter(ctx, aCopy.URL, file.DefaultFileOsMode)
+	if err != nil {
+		return nil, err
+	}
+	var dest io.WriteCloser = writer
+	if strings.HasSuffix(strings.ToLower(aCopy.URL), ".gz") {
+		dest = gzip.NewWriter(writer)
+	}
+	count, err := exportRows(rows, aCopy.NewWriter(dest, rows.Columns()))
+	if dest != writer {
+		if closeErr := dest.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result{totalRows: count, consumed: rows.consumed}, nil
+}
+
+func exportRows(rows *Rows, writer exec.CopyWriter) (int64, error) {
+	values := make([]driver.Value, len(rows.Columns()))
+	count := int64(0)
+	for {
+		err := rows.Next(values)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, err
+		}
+		if err = writer.Write(values); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, writer.Flush()
+}
+
+//openCopyReader opens URL with afs, .gz content is decompressed
+func openCopyReader(ctx context.Context, URL string) (io.ReadCloser, error) {
+	reader, err := afs.New().OpenURL(ctx, URL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(strings.ToLower(URL), ".gz") {
+		return reader, nil
+	}
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	return &gzipReadCloser{Reader: gzipReader, closer: reader}, nil
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	closer io.Closer
+}
+
+//Close closes gzip and underlying reader
+func (r *gzipReadCloser) Close() error {
+	err := r.Reader.Close()
+	if closeErr := r.closer.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package dyndb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStatement_CopyTo(t *testing.T) {
+	server := newTestServer(map[string]testHandler{
+		"DescribeTable":    staticOutput(publicationTable),
+		"ExecuteStatement": itemPages(`{"ISBN":{"S":"AAA"},"Price":{"N":"10"}}`),
+	})
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var testCases = []struct {
+		description     string
+		SQL             string
+		args            []interface{}
+		expect          string
+		expectParameter string
+	}{
+		{
+			description:     "positional parameter",
+			SQL:             "COPY (SELECT ISBN, Price FROM Publication WHERE ISBN = ?) TO '%v'",
+			args:            []interface{}{"AAA"},
+			expect:          `{"ISBN":"AAA","Price":10}`,
+			expectParameter: `[{"S":"AAA"}]`,
+		},
+		{
+			description:     "named parameter",
+			SQL:             "COPY (SELECT ISBN, Price FROM Publication WHERE ISBN = :isbn) TO '%v'",
+			args:            []interface{}{sql.Named("isbn", "AAA")},
+			expect:          `{"ISBN":"AAA","Price":10}`,
+			expectParameter: `[{"S":"AAA"}]`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		server.Reset()
+		location := filepath.Join(t.TempDir(), "publication.jsonl")
+		result, err := db.Exec(strings.Replace(testCase.SQL, "%v", location, 1), testCase.args...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		affected, _ := result.RowsAffected()
+		assert.EqualValues(t, 1, affected, testCase.description)
+		data, err := os.ReadFile(location)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, strings.TrimSpace(string(data)), testCase.description)
+		request := struct{ Parameters json.RawMessage }{}
+		if assert.Equal(t, 1, len(server.inputs["ExecuteStatement"]), testCase.description) {
+			_ = json.Unmarshal([]byte(server.inputs["ExecuteStatement"][0]), &request)
+			assert.EqualValues(t, testCase.expectParameter, string(request.Parameters), testCase.description)
+		}
+	}
+}
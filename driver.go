@@ -4,10 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
-	"fmt"
-	aws2 "github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/viant/scy/auth/aws"
 )
 
 const (
@@ -26,23 +22,14 @@ type Driver struct{}
 // See https://github.com/viant/dynamodb#dsn-data-source-name for how
 // the DSN string is formatted
 func (d Driver) Open(dsn string) (driver.Conn, error) {
-	if dsn == "" {
-		return nil, fmt.Errorf("dynamodb dsn was empty")
-	}
-	cfg, err := ParseDSN(dsn)
-	if err != nil {
-		return nil, err
-	}
-	awsConfig, err := aws.NewConfig(context.Background(), &cfg.Aws)
+	connector, err := NewConnector(dsn)
 	if err != nil {
 		return nil, err
 	}
+	return connector.Connect(context.Background())
+}
 
-	return &Connection{
-		cfg: awsConfig,
-		client: dynamodb.NewFromConfig(*awsConfig, func(options *dynamodb.Options) {
-			options.DefaultsMode = aws2.DefaultsModeLegacy
-		}),
-		executions: executions{maxSize: cfg.ExecMaxCache, cache: map[string]int{}},
-	}, nil
+//OpenConnector returns connector
+func (d Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	return NewConnector(dsn)
 }
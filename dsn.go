@@ -10,8 +10,10 @@ import (
 	_ "github.com/viant/scy/kms/blowfish"
 	"github.com/viant/toolbox"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -26,6 +28,14 @@ const (
 	dsnCredentialsKey   = "credKey"
 	dsnCredID           = "credID"
 	dsnExecCacheSize    = "execMaxCache"
+	dsnVersion          = "version"
+	dsnMaxAttempts      = "maxAttempts"
+	dsnMaxBackoff       = "maxBackoff"
+	dsnRetryBudget      = "retryBudget"
+	dsnConsistentRead   = "consistentRead"
+	dsnSlowThreshold    = "slowThreshold"
+	dsnSchemaTTL        = "schemaTTL"
+	dsnWildcardPages    = "wildcardPages"
 )
 
 //Config represent Connection config
@@ -36,6 +46,22 @@ type Config struct {
 	CredID  string
 	cred.Aws
 	ExecMaxCache int
+	//Versions maps table name to optimistic locking version attribute
+	Versions map[string]string
+	//MaxAttempts is the maximum number of request attempts, 1 disables retries
+	MaxAttempts int
+	//MaxBackoff is the maximum delay between retried attempts
+	MaxBackoff time.Duration
+	//RetryBudget is the retry token budget shared by all connector (sql.DB) connections, 0 for unlimited, -1 for SDK default
+	RetryBudget int
+	//ConsistentRead enables strongly consistent reads for SELECT by default
+	ConsistentRead bool
+	//SlowThreshold marks observed statements and pages taking at least threshold as slow
+	SlowThreshold time.Duration
+	//SchemaTTL is time to live of table descriptions cached by all DSN connections, 0 disables cache
+	SchemaTTL time.Duration
+	//WildcardPages is number of pages sampled to infer SELECT * columns, 0 samples all pages
+	WildcardPages int
 }
 
 // ParseDSN parses the DSN string to a Config
@@ -64,6 +90,9 @@ func ParseDSN(dsn string) (*Config, error) {
 	}
 	cfg.Region = path
 	cfg.ExecMaxCache = 100
+	cfg.RetryBudget = -1
+	cfg.SchemaTTL = time.Minute
+	cfg.WildcardPages = 1
 	if len(cfg.Values) > 0 {
 		if _, ok := cfg.Values[dsnSecret]; ok {
 			cfg.Secret = cfg.Values.Get(dsnSecret)
@@ -77,6 +106,61 @@ func ParseDSN(dsn string) (*Config, error) {
 			cfg.ExecMaxCache = toolbox.AsInt(cfg.Values.Get(dsnExecCacheSize))
 			delete(cfg.Values, dsnExecCacheSize)
 		}
+		if values, ok := cfg.Values[dsnVersion]; ok {
+			if cfg.Versions, err = parseVersions(values); err != nil {
+				return nil, err
+			}
+			delete(cfg.Values, dsnVersion)
+		}
+		if _, ok := cfg.Values[dsnMaxAttempts]; ok {
+			if cfg.MaxAttempts, err = strconv.Atoi(cfg.Values.Get(dsnMaxAttempts)); err != nil {
+				return nil, fmt.Errorf("invalid %v option: %w", dsnMaxAttempts, err)
+			}
+			if cfg.MaxAttempts <= 0 {
+				return nil, fmt.Errorf("invalid %v option: %v, expected a positive number", dsnMaxAttempts, cfg.MaxAttempts)
+			}
+			delete(cfg.Values, dsnMaxAttempts)
+		}
+		if _, ok := cfg.Values[dsnMaxBackoff]; ok {
+			if cfg.MaxBackoff, err = time.ParseDuration(cfg.Values.Get(dsnMaxBackoff)); err != nil {
+				return nil, fmt.Errorf("invalid %v option: %w", dsnMaxBackoff, err)
+			}
+			delete(cfg.Values, dsnMaxBackoff)
+		}
+		if _, ok := cfg.Values[dsnRetryBudget]; ok {
+			if cfg.RetryBudget, err = strconv.Atoi(cfg.Values.Get(dsnRetryBudget)); err != nil {
+				return nil, fmt.Errorf("invalid %v option: %w", dsnRetryBudget, err)
+			}
+			if cfg.RetryBudget < -1 {
+				return nil, fmt.Errorf("invalid %v option: %v, expected -1 for SDK default, 0 for unlimited or a positive number", dsnRetryBudget, cfg.RetryBudget)
+			}
+			delete(cfg.Values, dsnRetryBudget)
+		}
+		if _, ok := cfg.Values[dsnConsistentRead]; ok {
+			cfg.ConsistentRead = toolbox.AsBoolean(cfg.Values.Get(dsnConsistentRead))
+			delete(cfg.Values, dsnConsistentRead)
+		}
+		if _, ok := cfg.Values[dsnSlowThreshold]; ok {
+			if cfg.SlowThreshold, err = time.ParseDuration(cfg.Values.Get(dsnSlowThreshold)); err != nil {
+				return nil, fmt.Errorf("invalid %v option: %w", dsnSlowThreshold, err)
+			}
+			delete(cfg.Values, dsnSlowThreshold)
+		}
+		if _, ok := cfg.Values[dsnSchemaTTL]; ok {
+			if cfg.SchemaTTL, err = time.ParseDuration(cfg.Values.Get(dsnSchemaTTL)); err != nil {
+				return nil, fmt.Errorf("invalid %v option: %w", dsnSchemaTTL, err)
+			}
+			delete(cfg.Values, dsnSchemaTTL)
+		}
+		if _, ok := cfg.Values[dsnWildcardPages]; ok {
+			if cfg.WildcardPages, err = strconv.Atoi(cfg.Values.Get(dsnWildcardPages)); err != nil {
+				return nil, fmt.Errorf("invalid %v option: %w", dsnWildcardPages, err)
+			}
+			if cfg.WildcardPages < 0 {
+				return nil, fmt.Errorf("invalid %v option: %v, expected 0 for all pages or a positive number", dsnWildcardPages, cfg.WildcardPages)
+			}
+			delete(cfg.Values, dsnWildcardPages)
+		}
 		if _, ok := cfg.Values[dsnRoleArn]; ok {
 			if cfg.Session == nil {
 				cfg.Session = &cred.AwsSession{}
@@ -133,6 +217,21 @@ func ParseDSN(dsn string) (*Config, error) {
 	return cfg, nil
 }
 
+//parseVersions parses table:attribute[,table:attribute] version option values
+func parseVersions(values []string) (map[string]string, error) {
+	var result = map[string]string{}
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			pair := strings.SplitN(strings.TrimSpace(item), ":", 2)
+			if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+				return nil, fmt.Errorf("invalid %v option: %v, expected table:attribute", dsnVersion, item)
+			}
+			result[pair[0]] = pair[1]
+		}
+	}
+	return result, nil
+}
+
 func (c *Config) initialiseSecrets() error {
 	if c.CredURL != "" {
 		if URL, err := base64.RawURLEncoding.DecodeString(c.CredURL); err == nil {
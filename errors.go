@@ -0,0 +1,119 @@
+package dyndb
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/viant/dyndb/internal/exec"
+)
+
+var (
+	//ErrConditionFailed is returned when write condition is not satisfied
+	ErrConditionFailed = errors.New("condition check failed")
+	//ErrDuplicateKey is returned when INSERT without ON CONFLICT clause targets an existing item
+	ErrDuplicateKey = errors.New("duplicate key")
+	//ErrOptimisticLock is returned when versioned UPDATE or DELETE condition fails, item was modified or removed, it also matches ErrConditionFailed
+	ErrOptimisticLock = errors.New("optimistic lock: version mismatch")
+	//ErrThrottled is returned when request exceeded provisioned throughput or request limit
+	ErrThrottled = errors.New("request throttled")
+	//ErrTableNotFound is returned when table (or index) does not exist
+	ErrTableNotFound = errors.New("table not found")
+	//ErrTransactionConflict is returned when item is part of ongoing transaction
+	ErrTransactionConflict = errors.New("transaction conflict")
+	//ErrUnsupportedSQL is returned when SQL can not be translated to PartiQL
+	ErrUnsupportedSQL = exec.ErrUnsupported
+	//ErrValidation is returned for invalid statement, arguments or rejected request
+	ErrValidation = exec.ErrInvalid
+)
+
+//Error represents driver error, use errors.Is with Err* sentinels or errors.As to inspect it
+type Error struct {
+	//Kind is one of Err* sentinels or nil if error can not be classified
+	Kind error
+	//SQL is the offending SQL
+	SQL string
+	//PartiQL is the statement sent to DynamoDB
+	PartiQL string
+	//Retryable is true if the same request can be retried
+	Retryable bool
+	//Item is the stored item of ErrDuplicateKey read by key after the failed INSERT (nil if it could not be read),
+	//it is always nil for ErrConditionFailed and ErrOptimisticLock as ExecuteStatement of the supported SDK version can not return the item failing the condition
+	Item map[string]types.AttributeValue
+	//Err is the underlying error
+	Err error
+}
+
+//Error returns error message
+func (e *Error) Error() string {
+	if e.Kind == nil || errors.Is(e.Err, e.Kind) {
+		return e.Err.Error()
+	}
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+//Is returns true if target is the error kind
+func (e *Error) Is(target error) bool {
+	if e.Kind == nil {
+		return false
+	}
+	if e.Kind == ErrOptimisticLock && target == ErrConditionFailed {
+		return true
+	}
+	return target == e.Kind
+}
+
+//Unwrap returns underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+//newError returns classified driver error, an existing driver error is returned with SQL details
+func newError(err error, SQL, PartiQL string) error {
+	if err == nil {
+		return nil
+	}
+	var result *Error
+	if errors.As(err, &result) {
+		if result.SQL == "" {
+			result.SQL = SQL
+		}
+		if result.PartiQL == "" {
+			result.PartiQL = PartiQL
+		}
+		return result
+	}
+	result = &Error{Err: err, SQL: SQL, PartiQL: PartiQL}
+	result.Kind, result.Retryable = classify(err)
+	return result
+}
+
+//classify returns error kind and retryability
+func classify(err error) (error, bool) {
+	switch {
+	case errors.Is(err, exec.ErrUnsupported):
+		return ErrUnsupportedSQL, false
+	case errors.Is(err, exec.ErrInvalid):
+		return ErrValidation, false
+	}
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return nil, false
+	}
+	switch apiErr.ErrorCode() {
+	case "ConditionalCheckFailedException":
+		return ErrConditionFailed, false
+	case "DuplicateItemException":
+		return ErrDuplicateKey, false
+	case "ProvisionedThroughputExceededException", "RequestLimitExceeded", "ThrottlingException", "Throttling":
+		return ErrThrottled, true
+	case "ResourceNotFoundException", "TableNotFoundException", "IndexNotFoundException":
+		return ErrTableNotFound, false
+	case "TransactionConflictException", "TransactionInProgressException":
+		return ErrTransactionConflict, true
+	case "ValidationException", "SerializationException":
+		return ErrValidation, false
+	case "InternalServerError", "InternalFailure", "ServiceUnavailable":
+		return nil, true
+	}
+	return nil, false
+}
@@ -0,0 +1,79 @@
+package dyndb
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dyndb/internal/exec"
+	"testing"
+)
+
+func TestNewError(t *testing.T) {
+	var testCases = []struct {
+		description string
+		err         error
+		expect      error
+		retryable   bool
+	}{
+		{
+			description: "condition failed",
+			err:         fmt.Errorf("operation error: %w", &types.ConditionalCheckFailedException{}),
+			expect:      ErrConditionFailed,
+		},
+		{
+			description: "duplicate item",
+			err:         &types.DuplicateItemException{},
+			expect:      ErrDuplicateKey,
+		},
+		{
+			description: "throughput exceeded",
+			err:         &types.ProvisionedThroughputExceededException{},
+			expect:      ErrThrottled,
+			retryable:   true,
+		},
+		{
+			description: "generic throttling",
+			err:         &smithy.GenericAPIError{Code: "ThrottlingException"},
+			expect:      ErrThrottled,
+			retryable:   true,
+		},
+		{
+			description: "table not found",
+			err:         &types.ResourceNotFoundException{},
+			expect:      ErrTableNotFound,
+		},
+		{
+			description: "transaction conflict",
+			err:         &types.TransactionConflictException{},
+			expect:      ErrTransactionConflict,
+			retryable:   true,
+		},
+		{
+			description: "unsupported SQL",
+			err:         fmt.Errorf("%w: function foo", exec.ErrUnsupported),
+			expect:      ErrUnsupportedSQL,
+		},
+		{
+			description: "validation",
+			err:         &smithy.GenericAPIError{Code: "ValidationException"},
+			expect:      ErrValidation,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := newError(testCase.err, "SELECT 1", "SELECT * FROM t")
+		assert.True(t, errors.Is(err, testCase.expect), testCase.description)
+		var actual *Error
+		if !assert.True(t, errors.As(err, &actual), testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.retryable, actual.Retryable, testCase.description)
+		assert.EqualValues(t, "SELECT 1", actual.SQL, testCase.description)
+		assert.EqualValues(t, "SELECT * FROM t", actual.PartiQL, testCase.description)
+		assert.True(t, errors.Is(err, testCase.err), testCase.description)
+	}
+	lock := &Error{Kind: ErrOptimisticLock, Err: &types.ConditionalCheckFailedException{}}
+	assert.True(t, errors.Is(lock, ErrConditionFailed))
+}
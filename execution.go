@@ -1,38 +1,132 @@
 package dyndb
 
 import (
+	"container/list"
 	"github.com/viant/dyndb/internal/exec"
 	"sync"
+	"time"
 )
 
-type executions struct {
-	maxSize int
-	sync.RWMutex
-	cache      map[string]int
-	executions []*exec.Execution
+type (
+	//executionCache represents driver-wide LRU cache of parsed executions of a DSN, shared by all its connections
+	executionCache struct {
+		maxSize int
+		mux     sync.Mutex
+		entries map[string]*list.Element
+		lru     *list.List
+		stats   CacheStats
+	}
+
+	//cachedExecution represents cached execution, execution built from cached table description expires with the description
+	cachedExecution struct {
+		execution *exec.Execution
+		expiry    time.Time
+	}
+
+	//CacheStats represents execution cache stats
+	CacheStats struct {
+		Size      int
+		Hits      int64
+		Misses    int64
+		Evictions int64
+	}
+)
+
+var executionCaches = struct {
+	caches map[string]*executionCache
+	mux    sync.Mutex
+}{caches: map[string]*executionCache{}}
+
+//sharedExecutionCache returns execution cache shared by DSN connections, maxSize < 1 disables cache
+func sharedExecutionCache(dsn string, maxSize int) *executionCache {
+	executionCaches.mux.Lock()
+	defer executionCaches.mux.Unlock()
+	cache, ok := executionCaches.caches[dsn]
+	if !ok || cache.maxSize != maxSize {
+		cache = newExecutionCache(maxSize)
+		executionCaches.caches[dsn] = cache
+	}
+	return cache
+}
+
+//invalidateExecutions removes table executions cached by all connections
+func invalidateExecutions(table string) {
+	executionCaches.mux.Lock()
+	defer executionCaches.mux.Unlock()
+	for _, cache := range executionCaches.caches {
+		cache.invalidate(table)
+	}
+}
+
+func newExecutionCache(maxSize int) *executionCache {
+	return &executionCache{maxSize: maxSize, entries: map[string]*list.Element{}, lru: list.New()}
 }
 
-func (e *executions) Put(execution *exec.Execution) {
+//Put caches execution until expiry (zero for no expiry), the least recently used execution is evicted once cache exceeds max size
+func (e *executionCache) Put(execution *exec.Execution, expiry time.Time) {
 	if e.maxSize < 1 {
 		return
 	}
-	e.RWMutex.Lock()
-	defer e.RWMutex.Unlock()
-	if len(e.executions)+1 > e.maxSize {
-		e.cache = map[string]int{}
-		e.executions = e.executions[:0]
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	cached := &cachedExecution{execution: execution, expiry: expiry}
+	if elem, ok := e.entries[execution.SQL]; ok {
+		elem.Value = cached
+		e.lru.MoveToFront(elem)
+		return
+	}
+	e.entries[execution.SQL] = e.lru.PushFront(cached)
+	for e.lru.Len() > e.maxSize {
+		e.remove(e.lru.Back())
+		e.stats.Evictions++
 	}
-	size := len(e.executions)
-	e.cache[execution.SQL] = size
-	e.executions = append(e.executions, execution)
 }
 
-func (e *executions) Lookup(SQL string) *exec.Execution {
-	e.RWMutex.RLock()
-	pos, ok := e.cache[SQL]
-	defer e.RWMutex.RUnlock()
+//Lookup returns cached execution or nil, cached execution is shared by statements of all connections, expired execution is removed
+func (e *executionCache) Lookup(SQL string) *exec.Execution {
+	if e.maxSize < 1 {
+		return nil
+	}
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	elem, ok := e.entries[SQL]
+	if ok {
+		if cached := elem.Value.(*cachedExecution); !cached.expiry.IsZero() && time.Now().After(cached.expiry) {
+			e.remove(elem)
+			ok = false
+		}
+	}
 	if !ok {
+		e.stats.Misses++
 		return nil
 	}
-	return e.executions[pos]
+	e.stats.Hits++
+	e.lru.MoveToFront(elem)
+	return elem.Value.(*cachedExecution).execution
+}
+
+//Stats returns cache stats snapshot
+func (e *executionCache) Stats() CacheStats {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	result := e.stats
+	result.Size = e.lru.Len()
+	return result
+}
+
+func (e *executionCache) invalidate(table string) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	for elem := e.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if execution := elem.Value.(*cachedExecution).execution; execution.Table == table || (execution.Copy != nil && execution.Copy.Query != nil && execution.Copy.Query.Table == table) {
+			e.remove(elem)
+		}
+		elem = next
+	}
+}
+
+func (e *executionCache) remove(elem *list.Element) {
+	e.lru.Remove(elem)
+	delete(e.entries, elem.Value.(*cachedExecution).execution.SQL)
 }
@@ -0,0 +1,164 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dyndb/internal/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExecutionCache(t *testing.T) {
+	var testCases = []struct {
+		description string
+		maxSize     int
+		put         []string
+		lookup      []string
+		invalidate  string
+		expectFound []bool
+		expect      CacheStats
+	}{
+		{
+			description: "hit and miss",
+			maxSize:     2,
+			put:         []string{"Users:SELECT * FROM Users"},
+			lookup:      []string{"SELECT * FROM Users", "SELECT * FROM Orders"},
+			expectFound: []bool{true, false},
+			expect:      CacheStats{Size: 1, Hits: 1, Misses: 1},
+		},
+		{
+			description: "least recently used evicted",
+			maxSize:     2,
+			put:         []string{"Users:SELECT * FROM Users", "Orders:SELECT * FROM Orders", "@SELECT * FROM Users", "Items:SELECT * FROM Items"},
+			lookup:      []string{"SELECT * FROM Users", "SELECT * FROM Orders", "SELECT * FROM Items"},
+			expectFound: []bool{true, false, true},
+			expect:      CacheStats{Size: 2, Hits: 3, Misses: 1, Evictions: 1},
+		},
+		{
+			description: "table invalidated",
+			maxSize:     3,
+			put:         []string{"Users:SELECT * FROM Users", "Users:DELETE FROM Users WHERE Id = ?", "Orders:SELECT * FROM Orders"},
+			invalidate:  "Users",
+			lookup:      []string{"SELECT * FROM Users", "DELETE FROM Users WHERE Id = ?", "SELECT * FROM Orders"},
+			expectFound: []bool{false, false, true},
+			expect:      CacheStats{Size: 1, Hits: 1, Misses: 2},
+		},
+		{
+			description: "expired with table description",
+			maxSize:     2,
+			put:         []string{"~Users:SELECT * FROM Users", "Orders:SELECT * FROM Orders"},
+			lookup:      []string{"SELECT * FROM Users", "SELECT * FROM Orders"},
+			expectFound: []bool{false, true},
+			expect:      CacheStats{Size: 1, Hits: 1, Misses: 1},
+		},
+		{
+			description: "disabled",
+			put:         []string{"Users:SELECT * FROM Users"},
+			lookup:      []string{"SELECT * FROM Users"},
+			expectFound: []bool{false},
+		},
+	}
+
+	for _, testCase := range testCases {
+		cache := newExecutionCache(testCase.maxSize)
+		for _, entry := range testCase.put {
+			if entry[0] == '@' {
+				cache.Lookup(entry[1:])
+				continue
+			}
+			var expiry time.Time
+			if entry[0] == '~' {
+				entry, expiry = entry[1:], time.Now().Add(-time.Second)
+			}
+			parts := strings.SplitN(entry, ":", 2)
+			cache.Put(&exec.Execution{SQL: parts[1], Table: parts[0], Type: &exec.Type{}}, expiry)
+		}
+		if testCase.invalidate != "" {
+			cache.invalidate(testCase.invalidate)
+		}
+		for i, SQL := range testCase.lookup {
+			execution := cache.Lookup(SQL)
+			assert.EqualValues(t, testCase.expectFound[i], execution != nil, testCase.description+" "+SQL)
+		}
+		assert.EqualValues(t, testCase.expect, cache.Stats(), testCase.description)
+	}
+}
+
+func TestExecutionCache_Concurrent(t *testing.T) {
+	cache := newExecutionCache(8)
+	template := &exec.Execution{SQL: "SELECT * FROM Users", Table: "Users", Type: &exec.Type{}}
+	cache.Put(template, time.Time{})
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			SQL := fmt.Sprintf("SELECT * FROM Users WHERE Id = %v", i%10)
+			if cache.Lookup(SQL) == nil {
+				cache.Put(&exec.Execution{SQL: SQL, Table: "Users", Type: &exec.Type{}}, time.Time{})
+			}
+			execution := cache.Lookup("SELECT * FROM Users")
+			if execution != nil {
+				assert.True(t, execution == template)
+			}
+		}(i)
+	}
+	wg.Wait()
+	stats := cache.Stats()
+	assert.True(t, stats.Size <= 8)
+	assert.EqualValues(t, 32, stats.Hits+stats.Misses)
+}
+
+func TestExecutionCache_SharedQuery(t *testing.T) {
+	server := newTestServer(publicationPages())
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 2; i++ {
+		conn, err := db.Conn(ctx)
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+	var wg sync.WaitGroup
+	for _, conn := range conns {
+		wg.Add(1)
+		go func(conn *sql.Conn) {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				rows, err := conn.QueryContext(ctx, "SELECT ISBN, Price FROM Publication")
+				if !assert.Nil(t, err) {
+					return
+				}
+				var actual []string
+				for rows.Next() {
+					var ISBN string
+					var price float64
+					if assert.Nil(t, rows.Scan(&ISBN, &price)) {
+						actual = append(actual, fmt.Sprintf("%v:%v", ISBN, price))
+					}
+				}
+				assert.Nil(t, rows.Err())
+				assert.Nil(t, rows.Close())
+				assert.EqualValues(t, []string{"0-1:0", "0-2:1", "1-1:1", "1-2:1", "2-1:2", "2-2:1"}, actual)
+			}
+		}(conn)
+	}
+	wg.Wait()
+	assert.Nil(t, conns[0].Raw(func(driverConn interface{}) error {
+		stats := driverConn.(*Connection).ExecutionCacheStats()
+		assert.EqualValues(t, 1, stats.Size)
+		assert.True(t, stats.Hits >= 8, stats)
+		return nil
+	}))
+}
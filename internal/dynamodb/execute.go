@@ -1,8 +1,10 @@
 package dynamodb
 
 import (
+	"encoding/json"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/francoispqt/gojay"
 	"github.com/viant/dyndb/internal/exec"
 )
@@ -23,6 +25,7 @@ func (o *ExecuteStatementOutput) IsNil() bool {
 func (o *ExecuteStatementOutput) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
 	switch k {
 	case "Items":
+		o.Output.Rows = o.Output.Rows[:0] //regions refer to the current page data
 		err := dec.Array(o.Output)
 		if len(o.Output.Rows) > 0 {
 			o.Output.Rows[len(o.Output.Rows)-1].End = cursor(dec) - 1
@@ -35,6 +38,17 @@ func (o *ExecuteStatementOutput) UnmarshalJSONObject(dec *gojay.Decoder, k strin
 			o.NextToken = &value
 		}
 		return err
+	case "ConsumedCapacity":
+		var embedded gojay.EmbeddedJSON
+		if err := dec.EmbeddedJSON(&embedded); err != nil {
+			return err
+		}
+		capacity := &types.ConsumedCapacity{}
+		if err := json.Unmarshal(embedded, capacity); err != nil {
+			return err
+		}
+		o.ConsumedCapacity = capacity
+		return nil
 	}
 	return nil
 }
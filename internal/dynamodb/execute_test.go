@@ -1,6 +1,7 @@
 package dynamodb
 
 import (
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/francoispqt/gojay"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/dyndb/internal/exec"
@@ -13,6 +14,7 @@ func TestExecuteStatementOutput_UnmarshalJSONObject(t *testing.T) {
 		description string
 		input       string
 		expect      []string
+		capacity    *float64
 	}{
 		{
 			description: "array regions",
@@ -26,6 +28,13 @@ func TestExecuteStatementOutput_UnmarshalJSONObject(t *testing.T) {
 				`{"UserId":{"N":"1"},"Name":{"S":"User 1"}}`,
 			},
 		},
+		{
+			description: "consumed capacity",
+			input: `{"ConsumedCapacity":{"TableName":"Users","CapacityUnits":1.5,"Table":{"CapacityUnits":1.0},"GlobalSecondaryIndexes":{"ByName":{"CapacityUnits":0.5}}},
+						"Items":[{"UserId":{"N":"2"}}]}`,
+			expect:   []string{`{"UserId":{"N":"2"}}`},
+			capacity: aws.Float64(1.5),
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -38,6 +47,14 @@ func TestExecuteStatementOutput_UnmarshalJSONObject(t *testing.T) {
 			actual = append(actual, string(target.Data[region.Begin:region.End]))
 		}
 		assert.EqualValuesf(t, testCase.expect, actual, testCase.description)
+		if testCase.capacity == nil {
+			assert.Nilf(t, target.ConsumedCapacity, testCase.description)
+			continue
+		}
+		if assert.NotNilf(t, target.ConsumedCapacity, testCase.description) {
+			assert.EqualValuesf(t, *testCase.capacity, *target.ConsumedCapacity.CapacityUnits, testCase.description)
+			assert.EqualValuesf(t, 0.5, *target.ConsumedCapacity.GlobalSecondaryIndexes["ByName"].CapacityUnits, testCase.description)
+		}
 	}
 
 }
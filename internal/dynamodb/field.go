@@ -15,6 +15,9 @@ type FieldType struct {
 //UnmarshalJSONObject unmarshal object
 func (s *FieldType) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
 	var err error
+	if k == "NULL" {
+		return nil
+	}
 	s.Type = exec.Convert(k)
 	if k == "N" {
 		value := ""
@@ -32,3 +35,53 @@ func (s *FieldType) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
 func (s *FieldType) NKeys() int {
 	return 0
 }
+
+//PathType represents a nested document path type
+type PathType struct {
+	Type *exec.Type
+	Path *exec.Path
+	kind string
+}
+
+//UnmarshalJSONObject unmarshal object
+func (s *PathType) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
+	if s.kind == "M" {
+		if child := s.Path.Key(k); child != nil {
+			return s.discover(dec, child)
+		}
+		return nil
+	}
+	switch k {
+	case "M":
+		return dec.Object(&PathType{Type: s.Type, Path: s.Path, kind: k})
+	case "L":
+		return dec.Array(&PathType{Type: s.Type, Path: s.Path, kind: k})
+	}
+	return nil
+}
+
+//UnmarshalJSONArray unmarshal array
+func (s *PathType) UnmarshalJSONArray(dec *gojay.Decoder) error {
+	if child := s.Path.Item(dec.Index()); child != nil {
+		return s.discover(dec, child)
+	}
+	return dec.Object(&FieldType{})
+}
+
+func (s *PathType) discover(dec *gojay.Decoder, child *exec.Path) error {
+	if !child.IsProjected() {
+		return dec.Object(&PathType{Type: s.Type, Path: child})
+	}
+	field := &s.Type.Fields[child.Pos]
+	fieldType := &FieldType{}
+	err := dec.Object(fieldType)
+	if field.Type == nil {
+		field.Type = fieldType.Type
+	}
+	return err
+}
+
+//NKeys returns keys count
+func (s *PathType) NKeys() int {
+	return 0
+}
@@ -0,0 +1,92 @@
+package dynamodb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/francoispqt/gojay"
+)
+
+//Load loads client side items as statement output
+func (o *ExecuteStatementOutput) Load(items ...map[string]types.AttributeValue) error {
+	data, err := MarshalItems(items...)
+	if err != nil {
+		return err
+	}
+	o.ExecuteStatementOutput = &dynamodb.ExecuteStatementOutput{}
+	o.Output.Rows = nil
+	o.Output.Data = data
+	return gojay.Unmarshal(data, o)
+}
+
+//MarshalItems returns ExecuteStatement response body with supplied items
+func MarshalItems(items ...map[string]types.AttributeValue) ([]byte, error) {
+	var documents = make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		document, err := attributes(item)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false) //row decoder reads string values as is
+	if err := encoder.Encode(map[string]interface{}{"Items": documents}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buffer.Bytes()), nil
+}
+
+func attributes(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+	var result = make(map[string]interface{}, len(item))
+	for k, v := range item {
+		value, err := attribute(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal attribute %v, %w", k, err)
+		}
+		result[k] = value
+	}
+	return result, nil
+}
+
+//attribute returns DynamoDB JSON representation of attribute value
+func attribute(value types.AttributeValue) (map[string]interface{}, error) {
+	switch actual := value.(type) {
+	case *types.AttributeValueMemberS:
+		return map[string]interface{}{"S": actual.Value}, nil
+	case *types.AttributeValueMemberN:
+		return map[string]interface{}{"N": actual.Value}, nil
+	case *types.AttributeValueMemberB:
+		return map[string]interface{}{"B": actual.Value}, nil
+	case *types.AttributeValueMemberBOOL:
+		return map[string]interface{}{"BOOL": actual.Value}, nil
+	case *types.AttributeValueMemberNULL:
+		return map[string]interface{}{"NULL": true}, nil
+	case *types.AttributeValueMemberSS:
+		return map[string]interface{}{"SS": actual.Value}, nil
+	case *types.AttributeValueMemberNS:
+		return map[string]interface{}{"NS": actual.Value}, nil
+	case *types.AttributeValueMemberBS:
+		return map[string]interface{}{"BS": actual.Value}, nil
+	case *types.AttributeValueMemberL:
+		var list = make([]interface{}, 0, len(actual.Value))
+		for _, item := range actual.Value {
+			element, err := attribute(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, element)
+		}
+		return map[string]interface{}{"L": list}, nil
+	case *types.AttributeValueMemberM:
+		document, err := attributes(actual.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"M": document}, nil
+	}
+	return nil, fmt.Errorf("unsupported attribute value: %T", value)
+}
@@ -3,8 +3,11 @@ package dynamodb
 import (
 	"github.com/francoispqt/gojay"
 	"github.com/viant/dyndb/internal/exec"
+	"reflect"
 )
 
+var floatType = reflect.TypeOf(0.0)
+
 //Output represents optimized output
 type Output struct {
 	Type  *exec.Type
@@ -16,11 +19,19 @@ type Output struct {
 
 //UnmarshalJSONObject unmarshal object
 func (s *Output) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
-	s.Field = s.Type.Field(k)
+	if path := s.Type.Path(k); path != nil && !path.IsProjected() {
+		return dec.Object(&PathType{Type: s.Type, Path: path})
+	}
+	if s.Field = s.Type.Discover(k); s.Field == nil {
+		return nil
+	}
 	var err error
 	if s.Field.Type == nil {
+		s.FieldType.Type = nil
 		err = dec.Object(&s.FieldType)
-		s.Field.Type = s.FieldType.Type
+		if s.Field.Type = s.FieldType.Type; s.Type.Wildcard && s.Field.Type != nil && s.Field.Type.Kind() == reflect.Int {
+			s.Field.Type = floatType //SELECT * numbers are decimals as a later item can hold a fraction
+		}
 	}
 	return err
 }
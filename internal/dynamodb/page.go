@@ -0,0 +1,91 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	smithy "github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/francoispqt/gojay"
+	"io"
+	"io/ioutil"
+)
+
+//Page represents raw ExecuteStatement response, items are decoded by ExecuteStatementOutput.LoadPage
+type Page struct {
+	Data             []byte
+	NextToken        *string
+	ConsumedCapacity *types.ConsumedCapacity
+}
+
+// UnmarshalJSONObject implements gojay's UnmarshalerJSONObject, items are skipped
+func (p *Page) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
+	switch k {
+	case "NextToken":
+		var value string
+		err := dec.String(&value)
+		if err == nil {
+			p.NextToken = &value
+		}
+		return err
+	case "ConsumedCapacity":
+		var embedded gojay.EmbeddedJSON
+		if err := dec.EmbeddedJSON(&embedded); err != nil {
+			return err
+		}
+		p.ConsumedCapacity = &types.ConsumedCapacity{}
+		return json.Unmarshal(embedded, p.ConsumedCapacity)
+	}
+	return nil
+}
+
+// NKeys returns the number of keys to unmarshal
+func (p *Page) NKeys() int { return 0 }
+
+//PageMiddleware captures raw response page, unlike DeserializeMiddleware it does not touch statement type, thus it can run concurrently with rows decoding
+type PageMiddleware struct {
+	Page *Page
+}
+
+//ID returns ID
+func (m *PageMiddleware) ID() string {
+	return "OperationDeserializer"
+}
+
+//HandleDeserialize handle deserialize
+func (m *PageMiddleware) HandleDeserialize(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (out middleware.DeserializeOutput, metadata middleware.Metadata, err error) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+	var response, ok = out.RawResponse.(*smithyhttp.Response)
+	if !ok {
+		return out, metadata, &smithy.DeserializationError{Err: fmt.Errorf("unknown transport type %T", out.RawResponse)}
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return out, metadata, handleExecuteStatementException(response, &metadata)
+	}
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil && err != io.EOF {
+		return out, metadata, &smithy.DeserializationError{Err: fmt.Errorf("failed to read response body, %w", err)}
+	}
+	m.Page = &Page{Data: data}
+	if err = gojay.Unmarshal(data, m.Page); err != nil && err != io.EOF {
+		return out, metadata, &smithy.DeserializationError{Err: fmt.Errorf("failed to decode response body, %w", err)}
+	}
+	out.Result = &dynamodb.ExecuteStatementOutput{NextToken: m.Page.NextToken, ConsumedCapacity: m.Page.ConsumedCapacity}
+	return out, metadata, nil
+}
+
+//LoadPage decodes page fetched with PageMiddleware
+func (o *ExecuteStatementOutput) LoadPage(page *Page) error {
+	o.ExecuteStatementOutput = &dynamodb.ExecuteStatementOutput{}
+	o.Output.Data = page.Data
+	if err := gojay.Unmarshal(page.Data, o); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode response body, %w", err)
+	}
+	return nil
+}
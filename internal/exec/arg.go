@@ -0,0 +1,65 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"time"
+)
+
+var setTypes = []reflect.Type{reflect.TypeOf([]string{}), reflect.TypeOf([]int{}), reflect.TypeOf([]float64{})}
+
+//NormalizeArg returns argument value supported by Encode: driver.Valuer result (nil for invalid sql.Null* values),
+//dereferenced pointer, named scalar type converted to its underlying string, bool, int64, uint64 or float64,
+//named string, int and float64 slices are converted to their set types, attribute values, time, other slices, arrays, maps and structs are returned as is
+func NormalizeArg(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch actual := value.(type) {
+	case string, bool, int64, uint64, float64, []byte, time.Time, types.AttributeValue:
+		return value, nil
+	case driver.Valuer:
+		if rValue := reflect.ValueOf(value); rValue.Kind() == reflect.Ptr && rValue.IsNil() {
+			return nil, nil
+		}
+		result, err := actual.Value()
+		if err != nil {
+			return nil, err
+		}
+		return NormalizeArg(result)
+	}
+	rValue := reflect.ValueOf(value)
+	switch rValue.Kind() {
+	case reflect.Ptr:
+		if rValue.IsNil() {
+			return nil, nil
+		}
+		return NormalizeArg(rValue.Elem().Interface())
+	case reflect.String:
+		return rValue.String(), nil
+	case reflect.Bool:
+		return rValue.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rValue.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rValue.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rValue.Float(), nil
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		if rValue.Kind() != reflect.Slice {
+			return value, nil
+		}
+		if rValue.Type().Elem().Kind() == reflect.Uint8 {
+			return rValue.Bytes(), nil
+		}
+		for _, setType := range setTypes { //named string and number slices are encoded as sets
+			if rValue.Type() != setType && rValue.Type().ConvertibleTo(setType) {
+				return rValue.Convert(setType).Interface(), nil
+			}
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("unsupported type: %T", value)
+}
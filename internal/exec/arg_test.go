@@ -0,0 +1,57 @@
+package exec_test
+
+import (
+	"database/sql"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dyndb/internal/exec"
+	"testing"
+)
+
+func TestNormalizeArg(t *testing.T) {
+	type status string
+	type isbns []string
+	name := "Bob"
+	var nilName *string
+	var nilNull *sql.NullString
+	var testCases = []struct {
+		description string
+		value       interface{}
+		expect      interface{}
+		expectAttr  types.AttributeValue
+		hasError    bool
+	}{
+		{description: "valid null string", value: sql.NullString{String: "abc", Valid: true}, expect: "abc", expectAttr: &types.AttributeValueMemberS{Value: "abc"}},
+		{description: "invalid null int", value: sql.NullInt64{Int64: 3}, expect: nil, expectAttr: &types.AttributeValueMemberNULL{Value: true}},
+		{description: "nil null pointer", value: nilNull, expect: nil},
+		{description: "pointer", value: &name, expect: "Bob"},
+		{description: "nil pointer", value: nilName, expect: nil},
+		{description: "uint", value: uint16(7), expect: uint64(7), expectAttr: &types.AttributeValueMemberN{Value: "7"}},
+		{description: "int", value: 7, expect: int64(7), expectAttr: &types.AttributeValueMemberN{Value: "7"}},
+		{description: "float", value: float32(1.5), expect: 1.5, expectAttr: &types.AttributeValueMemberN{Value: "1.5"}},
+		{description: "named string", value: status("active"), expect: "active"},
+		{description: "named string slice", value: isbns{"1", "2"}, expect: []string{"1", "2"}, expectAttr: &types.AttributeValueMemberSS{Value: []string{"1", "2"}}},
+		{description: "bytes", value: []byte("abc"), expect: []byte("abc"), expectAttr: &types.AttributeValueMemberB{Value: []byte("abc")}},
+		{description: "channel", value: make(chan int), hasError: true},
+		{description: "function", value: func() {}, hasError: true},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := exec.NormalizeArg(testCase.value)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+		if testCase.expectAttr == nil {
+			continue
+		}
+		attr, err := exec.Encode(actual)
+		if assert.Nil(t, err, testCase.description) {
+			assert.EqualValues(t, testCase.expectAttr, attr, testCase.description)
+		}
+	}
+}
@@ -16,4 +16,7 @@ var nativeFunctions = map[string]bool{
 	"begins_with":    true,
 	"attribute_type": true,
 	"size":           true,
+	"list_append":    true,
+	"set_add":        true,
+	"set_delete":     true,
 }
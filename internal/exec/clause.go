@@ -0,0 +1,137 @@
+package exec
+
+import (
+	"strings"
+)
+
+//Clause represents SQL statement clause
+type Clause struct {
+	Keyword string
+	Text    string
+}
+
+//SplitClauses splits SQL into leading text and top level clauses starting with supplied keywords
+func SplitClauses(SQL string, keywords ...string) (string, []*Clause) {
+	var clauses []*Clause
+	prefix := ""
+	begin := 0
+	var current *Clause
+	walkTopLevel(SQL, func(i int) bool {
+		keyword := matchKeyword(SQL, i, keywords)
+		if keyword == "" {
+			return true
+		}
+		if current == nil {
+			prefix = SQL[begin:i]
+		} else {
+			current.Text = strings.TrimSpace(SQL[begin:i])
+		}
+		current = &Clause{Keyword: strings.ToUpper(strings.Join(strings.Fields(keyword), " "))}
+		clauses = append(clauses, current)
+		begin = i + len(keyword)
+		return true
+	})
+	if current == nil {
+		return SQL, nil
+	}
+	current.Text = strings.TrimSpace(SQL[begin:])
+	return prefix, clauses
+}
+
+//SplitList splits text by top level comma
+func SplitList(text string) []string {
+	var result []string
+	begin := 0
+	walkTopLevel(text, func(i int) bool {
+		if text[i] == ',' {
+			result = append(result, strings.TrimSpace(text[begin:i]))
+			begin = i + 1
+		}
+		return true
+	})
+	if last := strings.TrimSpace(text[begin:]); last != "" || len(result) > 0 {
+		result = append(result, last)
+	}
+	return result
+}
+
+//IndexTopLevel returns index of top level sep or -1
+func IndexTopLevel(text string, sep byte) int {
+	index := -1
+	walkTopLevel(text, func(i int) bool {
+		if text[i] == sep {
+			index = i
+			return false
+		}
+		return true
+	})
+	return index
+}
+
+//walkTopLevel calls fn with position of each character outside quotes and brackets
+func walkTopLevel(text string, fn func(i int) bool) {
+	depth := 0
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			continue
+		case c == '(' || c == '[' || c == '{':
+			depth++
+			continue
+		case c == ')' || c == ']' || c == '}':
+			depth--
+			continue
+		}
+		if depth == 0 && !fn(i) {
+			return
+		}
+	}
+}
+
+//matchKeyword returns matched keyword text, keyword words can be separated by any whitespace
+func matchKeyword(text string, i int, keywords []string) string {
+	if i > 0 && isPathChar(text[i-1]) {
+		return ""
+	}
+	for _, keyword := range keywords {
+		if end := matchWords(text, i, strings.Fields(keyword)); end != -1 {
+			return text[i:end]
+		}
+	}
+	return ""
+}
+
+func matchWords(text string, i int, words []string) int {
+	for j, word := range words {
+		if j > 0 {
+			offset := i
+			for i < len(text) && isWhitespace(text[i]) {
+				i++
+			}
+			if i == offset {
+				return -1
+			}
+		}
+		end := i + len(word)
+		if end > len(text) || !strings.EqualFold(text[i:end], word) {
+			return -1
+		}
+		if end < len(text) && isPathChar(text[end]) {
+			return -1
+		}
+		i = end
+	}
+	return i
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
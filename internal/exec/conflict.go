@@ -0,0 +1,108 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/viant/sqlparser"
+	"strings"
+)
+
+//Conflict represents INSERT ... ON CONFLICT [(key)] DO NOTHING | DO UPDATE SET path = expr[, ...] clause
+type Conflict struct {
+	DoNothing bool
+	Set       []*Assignment
+	Update    *Execution
+	offset    int
+	args      int
+}
+
+//ParseConflict strips ON CONFLICT clause from INSERT SQL
+func ParseConflict(SQL string) (string, *Conflict, error) {
+	prefix, clauses := SplitClauses(SQL, "ON CONFLICT")
+	if len(clauses) == 0 {
+		return SQL, nil, nil
+	}
+	if len(clauses) > 1 {
+		return "", nil, fmt.Errorf("%w: ON CONFLICT clause: %v", ErrUnsupported, SQL)
+	}
+	_, actions := SplitClauses(clauses[0].Text, "DO NOTHING", "DO UPDATE SET")
+	if len(actions) != 1 {
+		return "", nil, fmt.Errorf("%w: ON CONFLICT clause, expected DO NOTHING or DO UPDATE SET: %v", ErrUnsupported, clauses[0].Text)
+	}
+	result := &Conflict{}
+	switch action := actions[0]; action.Keyword {
+	case "DO NOTHING":
+		if action.Text != "" {
+			return "", nil, fmt.Errorf("%w: ON CONFLICT DO NOTHING clause: %v", ErrUnsupported, clauses[0].Text)
+		}
+		result.DoNothing = true
+	default:
+		update := &Update{}
+		if err := update.parseAssignments(action.Text); err != nil {
+			return "", nil, err
+		}
+		result.Set = update.Set
+	}
+	return strings.TrimSpace(prefix), result, nil
+}
+
+//initConflict creates UPDATE ... WHERE key = ? execution applied when inserted item already exists
+func (e *Execution) initConflict(desc *types.TableDescription) error {
+	conflict := e.Conflict
+	if conflict == nil || conflict.DoNothing {
+		return nil
+	}
+	if err := e.checkItem(e.Type.Keys); err != nil {
+		return err
+	}
+	var criteria []string
+	for _, key := range desc.KeySchema {
+		criteria = append(criteria, *key.AttributeName+" = ?")
+	}
+	aQuery, err := sqlparser.ParseQuery("SELECT * FROM " + e.Table + " WHERE " + strings.Join(criteria, " AND "))
+	if err != nil {
+		return err
+	}
+	update := &Update{Table: e.Table, Set: conflict.Set, Query: aQuery}
+	if e.Version != "" { //upsert does not expect any version, it only increments the existing one
+		increment, err := (&Update{Set: conflict.Set, Version: e.Version}).versionIncrement()
+		if err != nil {
+			return err
+		}
+		if increment != nil {
+			update.Set = append(append([]*Assignment{}, conflict.Set...), increment)
+		}
+	}
+	if e.Returning != nil {
+		update.Returning = &Returning{Mode: ReturningAllNew, Columns: e.Returning.Columns}
+	}
+	if conflict.Update, err = NewUpdate(e.Table, update, desc); err != nil {
+		return err
+	}
+	conflict.offset = e.Type.BindingLen
+	conflict.args = conflict.Update.Type.BindingLen - len(desc.KeySchema)
+	return nil
+}
+
+//ConflictArgs returns ON CONFLICT DO UPDATE arguments followed by inserted item key values
+func (e *Execution) ConflictArgs(state *State) ([]driver.NamedValue, error) {
+	conflict := e.Conflict
+	if conflict.offset > len(state.Args) {
+		return nil, fmt.Errorf("%w: missing arguments: expected %v, but had %v", ErrInvalid, e.NumInput(), len(state.Args))
+	}
+	var result = append([]driver.NamedValue{}, state.Args[conflict.offset:]...)
+	for _, param := range conflict.Update.Type.Criteria {
+		for _, item := range e.item {
+			if item == nil || item.Name != param.Name {
+				continue
+			}
+			value, err := itemValue(state, item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, driver.NamedValue{Ordinal: len(result) + 1, Value: value})
+		}
+	}
+	return result, nil
+}
@@ -11,6 +11,7 @@ import (
 var (
 	ifaceType  = reflect.TypeOf(new(interface{}))
 	intType    = reflect.TypeOf(int(0))
+	floatType  = reflect.TypeOf(0.0)
 	stringType = reflect.TypeOf("")
 	boolType   = reflect.TypeOf(true)
 	bytesType  = reflect.TypeOf([]byte{})
@@ -18,6 +19,14 @@ var (
 	mapType    = reflect.TypeOf(map[string]interface{}{})
 )
 
+//WildcardType returns SELECT * column type for attribute type, numbers are decimals as any later item can hold a fraction
+func WildcardType(attributeType string) reflect.Type {
+	if attributeType == "N" {
+		return floatType
+	}
+	return Convert(attributeType)
+}
+
 //Convert converts attribute to  relect type
 func Convert(attributeType string) reflect.Type {
 	switch attributeType {
@@ -38,7 +47,7 @@ func Convert(attributeType string) reflect.Type {
 	case "L":
 		return listType
 	case "M":
-		return reflect.SliceOf(mapType)
+		return mapType
 	case "NULL":
 		return ifaceType
 	}
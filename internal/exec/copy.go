@@ -0,0 +1,441 @@
+package exec
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Copy formats
+const (
+	FormatJSONL = "JSONL"
+	FormatCSV   = "CSV"
+)
+
+var copyExpr = regexp.MustCompile(`(?is)^\s*(FROM|TO)\s+'([^']+)'(?:\s+(?:WITH\s+)?FORMAT\s+(\w+))?\s*$`)
+
+type (
+	//Copy represents COPY table FROM 'url' or COPY (SELECT ...) | table TO 'url' statement
+	Copy struct {
+		URL    string
+		Format string
+		//Query represents exported query, it is set by NewCopy caller for COPY TO
+		Query *Execution
+		//QuerySQL represents exported query SQL
+		QuerySQL string
+	}
+
+	//CopyRow represents COPY FROM row, Err reports row specific decoding error
+	CopyRow struct {
+		//Row is 1 based record ordinal
+		Row  int
+		Item map[string]types.AttributeValue
+		Err  error
+	}
+
+	//CopyReader reads COPY FROM rows, it returns io.EOF when there are no more rows
+	CopyReader interface {
+		Read() (*CopyRow, error)
+	}
+
+	//CopyWriter writes COPY TO rows
+	CopyWriter interface {
+		Write(values []driver.Value) error
+		Flush() error
+	}
+
+	jsonReader struct {
+		scanner *bufio.Scanner
+		decoder *json.Decoder
+		row     int
+	}
+
+	csvReader struct {
+		reader *csv.Reader
+		header []string
+		types  []string
+		row    int
+	}
+
+	jsonWriter struct {
+		encoder *json.Encoder
+		columns []string
+	}
+
+	csvWriter struct {
+		writer  *csv.Writer
+		columns []string
+		record  []string
+		header  bool
+	}
+)
+
+//ParseCopy parses COPY statement, it returns KindCopyFrom or KindCopyTo, table and copy details
+func ParseCopy(SQL string) (Kind, string, *Copy, error) {
+	text := strings.TrimRight(strings.TrimSpace(SQL), ";")
+	if len(text) < 4 || !strings.EqualFold(text[:4], "COPY") {
+		return KindUndefined, "", nil, fmt.Errorf("%w: %v", ErrUnsupported, SQL)
+	}
+	text = strings.TrimSpace(text[4:])
+	result := &Copy{}
+	table := ""
+	if strings.HasPrefix(text, "(") {
+		end := closingParen(text)
+		if end == -1 {
+			return KindUndefined, "", nil, fmt.Errorf("%w: unbalanced parenthesis: %v", ErrInvalid, SQL)
+		}
+		result.QuerySQL = strings.TrimSpace(text[1:end])
+		text = text[end+1:]
+	} else {
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			return KindUndefined, "", nil, fmt.Errorf("%w: expected table: %v", ErrInvalid, SQL)
+		}
+		table = tableName(fields[0])
+		text = strings.TrimSpace(text)[len(fields[0]):]
+	}
+	match := copyExpr.FindStringSubmatch(text)
+	if match == nil {
+		return KindUndefined, "", nil, fmt.Errorf("%w: expected COPY table FROM 'url' [FORMAT JSONL|CSV] or COPY (SELECT ...) TO 'url' [FORMAT JSONL|CSV]: %v", ErrInvalid, SQL)
+	}
+	result.URL = match[2]
+	var err error
+	if result.Format, err = copyFormat(match[3], result.URL); err != nil {
+		return KindUndefined, "", nil, err
+	}
+	if strings.EqualFold(match[1], "FROM") {
+		if result.QuerySQL != "" {
+			return KindUndefined, "", nil, fmt.Errorf("%w: COPY FROM expects table: %v", ErrInvalid, SQL)
+		}
+		return KindCopyFrom, table, result, nil
+	}
+	if result.QuerySQL == "" {
+		result.QuerySQL = "SELECT * FROM " + table
+	}
+	return KindCopyTo, table, result, nil
+}
+
+//NewCopy creates COPY FROM or COPY TO execution
+func NewCopy(kind Kind, table string, aCopy *Copy) (*Execution, error) {
+	switch kind {
+	case KindCopyFrom:
+	case KindCopyTo:
+		if aCopy.Query == nil || aCopy.Query.Parti == nil || aCopy.Query.Kind != KindUndefined {
+			return nil, fmt.Errorf("%w: COPY TO expects SELECT: %v", ErrUnsupported, aCopy.QuerySQL)
+		}
+		table = aCopy.Query.Table
+	default:
+		return nil, fmt.Errorf("%w: copy kind: %v", ErrUnsupported, kind)
+	}
+	result := &Execution{Kind: kind, Table: table, Type: NewType(false), Copy: aCopy}
+	result.initState()
+	return result, nil
+}
+
+func copyFormat(format, URL string) (string, error) {
+	if format != "" {
+		switch format = strings.ToUpper(format); format {
+		case FormatJSONL, FormatCSV:
+			return format, nil
+		case "JSON", "NDJSON":
+			return FormatJSONL, nil
+		}
+		return "", fmt.Errorf("%w: COPY format %v, supported: JSONL, CSV", ErrUnsupported, format)
+	}
+	name := strings.TrimSuffix(strings.ToLower(URL), ".gz")
+	switch {
+	case strings.HasSuffix(name, ".csv"):
+		return FormatCSV, nil
+	case strings.HasSuffix(name, ".jsonl"), strings.HasSuffix(name, ".ndjson"), strings.HasSuffix(name, ".json"):
+		return FormatJSONL, nil
+	}
+	return "", fmt.Errorf("%w: unable to infer COPY format from %v, use FORMAT JSONL|CSV", ErrInvalid, URL)
+}
+
+//closingParen returns index of parenthesis closing the first one or -1
+func closingParen(text string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+//NewReader creates COPY FROM reader, CSV header fields can declare attribute type with name:S|N|BOOL suffix, key attributes default to table definition
+func (c *Copy) NewReader(reader io.Reader, desc *types.TableDescription) (CopyReader, error) {
+	if c.Format == FormatCSV {
+		return newCSVReader(reader, desc)
+	}
+	buffered := bufio.NewReader(reader)
+	if data, _ := buffered.Peek(64); len(bytes.TrimSpace(data)) > 0 && bytes.TrimSpace(data)[0] == '[' {
+		decoder := json.NewDecoder(buffered)
+		decoder.UseNumber()
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return &jsonReader{decoder: decoder}, nil
+	}
+	scanner := bufio.NewScanner(buffered)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &jsonReader{scanner: scanner}, nil
+}
+
+//Read reads JSON Lines row, JSON array elements are also supported
+func (r *jsonReader) Read() (*CopyRow, error) {
+	if r.decoder != nil {
+		if !r.decoder.More() {
+			return nil, io.EOF
+		}
+		r.row++
+		var value interface{}
+		if err := r.decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("failed to decode row %v: %w", r.row, err)
+		}
+		return newJSONRow(r.row, value), nil
+	}
+	for r.scanner.Scan() {
+		r.row++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return &CopyRow{Row: r.row, Err: fmt.Errorf("%w: %v", ErrInvalid, err)}, nil
+		}
+		return newJSONRow(r.row, value), nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func newJSONRow(row int, value interface{}) *CopyRow {
+	result := &CopyRow{Row: row}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		result.Err = fmt.Errorf("%w: expected JSON object, but had %T", ErrInvalid, value)
+		return result
+	}
+	item, err := jsonAttributeValue(object)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Item = item.(*types.AttributeValueMemberM).Value
+	return result
+}
+
+//jsonAttributeValue converts JSON decoded (with UseNumber) value, numbers keep their text representation
+func jsonAttributeValue(value interface{}) (types.AttributeValue, error) {
+	switch actual := value.(type) {
+	case nil:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case bool:
+		return &types.AttributeValueMemberBOOL{Value: actual}, nil
+	case string:
+		return &types.AttributeValueMemberS{Value: actual}, nil
+	case json.Number:
+		return &types.AttributeValueMemberN{Value: actual.String()}, nil
+	case []interface{}:
+		result := &types.AttributeValueMemberL{Value: make([]types.AttributeValue, 0, len(actual))}
+		for _, item := range actual {
+			itemValue, err := jsonAttributeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result.Value = append(result.Value, itemValue)
+		}
+		return result, nil
+	case map[string]interface{}:
+		result := &types.AttributeValueMemberM{Value: make(map[string]types.AttributeValue, len(actual))}
+		for key, item := range actual {
+			itemValue, err := jsonAttributeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result.Value[key] = itemValue
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported JSON value type %T", ErrInvalid, value)
+}
+
+func newCSVReader(reader io.Reader, desc *types.TableDescription) (*csvReader, error) {
+	result := &csvReader{reader: csv.NewReader(reader)}
+	result.reader.FieldsPerRecord = -1
+	header, err := result.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: CSV header was missing", ErrInvalid)
+		}
+		return nil, err
+	}
+	defined := map[string]string{}
+	if desc != nil {
+		for _, attr := range desc.AttributeDefinitions {
+			defined[*attr.AttributeName] = string(attr.AttributeType)
+		}
+	}
+	for _, field := range header {
+		name, attrType := strings.TrimSpace(field), ""
+		if index := strings.LastIndexByte(name, ':'); index != -1 {
+			name, attrType = name[:index], strings.ToUpper(name[index+1:])
+			switch attrType {
+			case "S", "N", "BOOL":
+			default:
+				return nil, fmt.Errorf("%w: CSV column %v type %v, supported: S, N, BOOL", ErrUnsupported, name, attrType)
+			}
+		}
+		if attrType == "" {
+			if attrType = defined[name]; attrType == "" {
+				attrType = "S"
+			}
+		}
+		result.header = append(result.header, name)
+		result.types = append(result.types, attrType)
+	}
+	return result, nil
+}
+
+//Read reads CSV row, empty values are skipped
+func (r *csvReader) Read() (*CopyRow, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	r.row++
+	result := &CopyRow{Row: r.row}
+	if len(record) != len(r.header) {
+		result.Err = fmt.Errorf("%w: expected %v fields, but had %v", ErrInvalid, len(r.header), len(record))
+		return result, nil
+	}
+	result.Item = make(map[string]types.AttributeValue, len(record))
+	for i, text := range record {
+		if text == "" {
+			continue
+		}
+		switch r.types[i] {
+		case "N":
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				result.Err = fmt.Errorf("%w: column %v: invalid number %v", ErrInvalid, r.header[i], text)
+				return result, nil
+			}
+			result.Item[r.header[i]] = &types.AttributeValueMemberN{Value: text}
+		case "BOOL":
+			value, err := strconv.ParseBool(text)
+			if err != nil {
+				result.Err = fmt.Errorf("%w: column %v: invalid bool %v", ErrInvalid, r.header[i], text)
+				return result, nil
+			}
+			result.Item[r.header[i]] = &types.AttributeValueMemberBOOL{Value: value}
+		default:
+			result.Item[r.header[i]] = &types.AttributeValueMemberS{Value: text}
+		}
+	}
+	return result, nil
+}
+
+//NewWriter creates COPY TO writer
+func (c *Copy) NewWriter(writer io.Writer, columns []string) CopyWriter {
+	if c.Format == FormatCSV {
+		return &csvWriter{writer: csv.NewWriter(writer), columns: columns, record: make([]string, len(columns))}
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	return &jsonWriter{encoder: encoder, columns: columns}
+}
+
+//Write writes JSON line
+func (w *jsonWriter) Write(values []driver.Value) error {
+	row := make(map[string]interface{}, len(w.columns))
+	for i, column := range w.columns {
+		row[column] = values[i]
+	}
+	return w.encoder.Encode(row)
+}
+
+//Flush flushes writer
+func (w *jsonWriter) Flush() error {
+	return nil
+}
+
+//Write writes CSV record, header is written before the first record
+func (w *csvWriter) Write(values []driver.Value) error {
+	if !w.header {
+		w.header = true
+		if err := w.writer.Write(w.columns); err != nil {
+			return err
+		}
+	}
+	for i, value := range values {
+		text, err := csvValue(value)
+		if err != nil {
+			return err
+		}
+		w.record[i] = text
+	}
+	return w.writer.Write(w.record)
+}
+
+//Flush flushes writer, header is written even if there were no records
+func (w *csvWriter) Flush() error {
+	if !w.header {
+		w.header = true
+		if err := w.writer.Write(w.columns); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func csvValue(value driver.Value) (string, error) {
+	switch actual := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return actual, nil
+	case []byte:
+		return string(actual), nil
+	case int:
+		return strconv.Itoa(actual), nil
+	case int64:
+		return strconv.FormatInt(actual, 10), nil
+	case float64:
+		return strconv.FormatFloat(actual, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(actual), nil
+	case time.Time:
+		return actual.Format(time.RFC3339Nano), nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
@@ -0,0 +1,181 @@
+package exec_test
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dyndb/internal/exec"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseCopy(t *testing.T) {
+	var testCases = []struct {
+		description string
+		SQL         string
+		kind        exec.Kind
+		table       string
+		expect      *exec.Copy
+		hasError    bool
+	}{
+		{
+			description: "copy from with format",
+			SQL:         "COPY Publication FROM 'mem://localhost/data/publication.txt' FORMAT csv",
+			kind:        exec.KindCopyFrom,
+			table:       "Publication",
+			expect:      &exec.Copy{URL: "mem://localhost/data/publication.txt", Format: exec.FormatCSV},
+		},
+		{
+			description: "copy from with inferred gzip format",
+			SQL:         "copy Publication from 'file:///tmp/publication.jsonl.gz';",
+			kind:        exec.KindCopyFrom,
+			table:       "Publication",
+			expect:      &exec.Copy{URL: "file:///tmp/publication.jsonl.gz", Format: exec.FormatJSONL},
+		},
+		{
+			description: "copy query to",
+			SQL:         "COPY (SELECT ISBN, Name FROM Publication WHERE ISBN IN ('1', '2')) TO 'mem://localhost/out.csv'",
+			kind:        exec.KindCopyTo,
+			expect:      &exec.Copy{URL: "mem://localhost/out.csv", Format: exec.FormatCSV, QuerySQL: "SELECT ISBN, Name FROM Publication WHERE ISBN IN ('1', '2')"},
+		},
+		{
+			description: "copy table to",
+			SQL:         "COPY Publication TO 'mem://localhost/out.json' WITH FORMAT JSONL",
+			kind:        exec.KindCopyTo,
+			table:       "Publication",
+			expect:      &exec.Copy{URL: "mem://localhost/out.json", Format: exec.FormatJSONL, QuerySQL: "SELECT * FROM Publication"},
+		},
+		{
+			description: "unknown format",
+			SQL:         "COPY Publication FROM 'mem://localhost/data.txt'",
+			hasError:    true,
+		},
+		{
+			description: "copy query from",
+			SQL:         "COPY (SELECT * FROM Publication) FROM 'mem://localhost/data.csv'",
+			hasError:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		kind, table, actual, err := exec.ParseCopy(testCase.SQL)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.kind, kind, testCase.description)
+		assert.EqualValues(t, testCase.table, table, testCase.description)
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
+
+func TestCopy_NewReader(t *testing.T) {
+	desc := &types.TableDescription{
+		AttributeDefinitions: []types.AttributeDefinition{{AttributeName: stringPtr("Id"), AttributeType: types.ScalarAttributeTypeN}},
+	}
+	var testCases = []struct {
+		description string
+		format      string
+		input       string
+		expect      []map[string]interface{}
+		rowErrors   []int
+	}{
+		{
+			description: "json lines",
+			format:      exec.FormatJSONL,
+			input:       "{\"Id\":1,\"Name\":\"Bob\",\"Tags\":[\"a\"]}\n\n{\"Id\":2.5,\"Info\":{\"Active\":true}}\nnot json\n[1]\n",
+			expect: []map[string]interface{}{
+				{"Id": 1.0, "Name": "Bob", "Tags": []interface{}{"a"}},
+				{"Id": 2.5, "Info": map[string]interface{}{"Active": true}},
+			},
+			rowErrors: []int{4, 5},
+		},
+		{
+			description: "json array",
+			format:      exec.FormatJSONL,
+			input:       " [{\"Id\":1},{\"Id\":2,\"Name\":null}]",
+			expect: []map[string]interface{}{
+				{"Id": 1.0},
+				{"Id": 2.0, "Name": nil},
+			},
+		},
+		{
+			description: "csv with declared and key types",
+			format:      exec.FormatCSV,
+			input:       "Id,Name,Score:N,Active:BOOL\n1,Bob,3.5,true\n2,,,\nx,Ann,1,false\n3,Ann\n",
+			expect: []map[string]interface{}{
+				{"Id": 1.0, "Name": "Bob", "Score": 3.5, "Active": true},
+				{"Id": 2.0},
+			},
+			rowErrors: []int{3, 4},
+		},
+	}
+
+	for _, testCase := range testCases {
+		aCopy := &exec.Copy{Format: testCase.format}
+		reader, err := aCopy.NewReader(strings.NewReader(testCase.input), desc)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []map[string]interface{}
+		var rowErrors []int
+		for {
+			row, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if !assert.Nil(t, err, testCase.description) {
+				break
+			}
+			if row.Err != nil {
+				rowErrors = append(rowErrors, row.Row)
+				continue
+			}
+			var item map[string]interface{}
+			assert.Nil(t, attributevalue.UnmarshalMap(row.Item, &item), testCase.description)
+			actual = append(actual, item)
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+		assert.EqualValues(t, testCase.rowErrors, rowErrors, testCase.description)
+	}
+}
+
+func TestCopy_NewWriter(t *testing.T) {
+	columns := []string{"Id", "Name", "Tags"}
+	rows := [][]driver.Value{
+		{1, "Bob, Jr", []string{"a", "b"}},
+		{2, nil, nil},
+	}
+	var testCases = []struct {
+		description string
+		format      string
+		expect      string
+	}{
+		{
+			description: "json lines",
+			format:      exec.FormatJSONL,
+			expect:      "{\"Id\":1,\"Name\":\"Bob, Jr\",\"Tags\":[\"a\",\"b\"]}\n{\"Id\":2,\"Name\":null,\"Tags\":null}\n",
+		},
+		{
+			description: "csv",
+			format:      exec.FormatCSV,
+			expect:      "Id,Name,Tags\n1,\"Bob, Jr\",\"[\"\"a\"\",\"\"b\"\"]\"\n2,,\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		buffer := new(bytes.Buffer)
+		writer := (&exec.Copy{Format: testCase.format}).NewWriter(buffer, columns)
+		for _, row := range rows {
+			assert.Nil(t, writer.Write(row), testCase.description)
+		}
+		assert.Nil(t, writer.Flush(), testCase.description)
+		assert.EqualValues(t, testCase.expect, buffer.String(), testCase.description)
+	}
+}
@@ -14,6 +14,22 @@ var encoder = attributevalue.NewEncoder()
 //Encode encodes value
 func Encode(value interface{}) (types.AttributeValue, error) {
 	switch actual := value.(type) {
+	case nil:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case string:
+		return &types.AttributeValueMemberS{Value: actual}, nil
+	case bool:
+		return &types.AttributeValueMemberBOOL{Value: actual}, nil
+	case int64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(actual, 10)}, nil
+	case uint64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatUint(actual, 10)}, nil
+	case float64:
+		return &types.AttributeValueMemberN{Value: strconv.FormatFloat(actual, 'f', -1, 64)}, nil
+	case []byte:
+		return &types.AttributeValueMemberB{Value: actual}, nil
+	case types.AttributeValue:
+		return actual, nil
 	case []string:
 		return &types.AttributeValueMemberSS{Value: actual}, nil
 	case []int:
@@ -137,7 +153,7 @@ func (w *wrapper) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
 		w.value = value
 		return err
 	case "L":
-		l := list{}
+		l := list{object: &wrapper{}}
 		err = dec.Array(&l)
 		w.value = l.items
 		return err
@@ -0,0 +1,10 @@
+package exec
+
+import "errors"
+
+var (
+	//ErrUnsupported is returned for SQL that can not be translated to PartiQL
+	ErrUnsupported = errors.New("unsupported SQL")
+	//ErrInvalid is returned for invalid statement or arguments
+	ErrInvalid = errors.New("invalid statement")
+)
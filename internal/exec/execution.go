@@ -13,10 +13,10 @@ import (
 	"github.com/viant/sqlparser/node"
 	"github.com/viant/sqlparser/query"
 	"github.com/viant/sqlparser/table"
-	"github.com/viant/sqlparser/update"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 //Kind represents execution king
@@ -29,26 +29,61 @@ const (
 	KindCreateTable
 	//KindDropTable drop table
 	KindDropTable
+	//KindInsert insert
+	KindInsert
+	//KindUpdate update
+	KindUpdate
+	//KindDelete delete
+	KindDelete
+	//KindExplain explain plan
+	KindExplain
+	//KindShowTables show tables
+	KindShowTables
+	//KindDescribe describe table
+	KindDescribe
+	//KindShowIndexes show table indexes
+	KindShowIndexes
+	//KindSchema information_schema query
+	KindSchema
+	//KindStream table change stream query
+	KindStream
+	//KindCopyFrom COPY table FROM file
+	KindCopyFrom
+	//KindCopyTo COPY query TO file
+	KindCopyTo
 )
 
 type (
 	//Execution represent execution
 	Execution struct {
-		Kind          Kind
-		SQL           string
-		Table         string
-		HasTable      bool
-		query         *query.Select
-		insert        *insert.Statement
-		update        *update.Statement
-		delete        *del.Statement
-		Create        *table.Create
-		Drop          *table.Drop
-		Type          *Type
-		Parti         *PartiQL
-		Limit         *int32
-		state         sync.Pool
-		criteriaParam string
+		Kind           Kind
+		SQL            string
+		Table          string
+		HasTable       bool
+		query          *query.Select
+		insert         *insert.Statement
+		update         *Update
+		delete         *del.Statement
+		Create         *table.Create
+		Drop           *table.Drop
+		Type           *Type
+		Parti          *PartiQL
+		Limit          *int32
+		ConsistentRead *bool
+		Returning      *Returning
+		Conflict       *Conflict
+		Replace        bool
+		Version        string
+		Copy           *Copy
+		Names          []string
+		state          *sync.Pool
+		queryType      atomic.Pointer[Type] //initialised type shared by query states
+		queryStates    sync.Pool
+		criteriaParam  string
+		item           []*Parameter
+		virtual        *virtualQuery
+		//Items holds client side synthesized rows
+		Items []map[string]types.AttributeValue
 	}
 
 	//PartiQL represent PrtiQA
@@ -57,9 +92,18 @@ type (
 	}
 )
 
-//ReleaseState releases state
+//ReleaseState releases state, the first released initialised query state type becomes shared by subsequent query states
 func (e *Execution) ReleaseState(state *State) {
-	e.state.Put(state)
+	if e.state != nil && state.Type == e.Type {
+		e.state.Put(state)
+		return
+	}
+	if state.Type.Wildcard || !state.Type.IsResolved() {
+		return
+	}
+	if e.queryType.CompareAndSwap(nil, state.Type) || e.queryType.Load() == state.Type {
+		e.queryStates.Put(state)
+	}
 }
 
 func (e *Execution) initCriteria() error {
@@ -95,7 +139,7 @@ func (e *Execution) parseCriteria(x node.Node) error {
 	var err error
 	switch actual := x.(type) {
 	case *expr.Ident, *expr.Selector:
-		e.criteriaParam = sqlparser.Stringify(actual)
+		e.criteriaParam = stringify(actual)
 	case *expr.Placeholder:
 		e.Type.AddCriteria(NewPlaceholder(e.criteriaParam))
 	case *expr.Parenthesis:
@@ -126,7 +170,9 @@ func (e *Execution) parseCriteria(x node.Node) error {
 			}
 			for _, item := range list {
 				if _, ok := item.Expr.(*expr.Placeholder); ok {
-					e.Type.AddCriteria(NewPlaceholder(paramName))
+					param := NewPlaceholder(paramName)
+					param.InList = len(list) == 1
+					e.Type.AddCriteria(param)
 				}
 			}
 			if nodeToCheck != nil {
@@ -140,7 +186,7 @@ func (e *Execution) parseCriteria(x node.Node) error {
 		return e.parseCriteria(actual.Y)
 	case *expr.Literal:
 	default:
-		panic(fmt.Sprintf("%T: unupported", actual))
+		return fmt.Errorf("%w: criteria node %T", ErrUnsupported, actual)
 	}
 	return nil
 }
@@ -148,7 +194,7 @@ func (e *Execution) parseCriteria(x node.Node) error {
 func (e *Execution) appendPlaceholder(x, y node.Node) (string, bool, error) {
 	paramName := ""
 	if ident := expr.Identity(x); ident != nil {
-		paramName = sqlparser.Stringify(ident)
+		paramName = stringify(ident)
 		if _, ok := y.(*expr.Placeholder); ok {
 			e.Type.AddCriteria(NewPlaceholder(paramName))
 			return paramName, true, nil
@@ -182,11 +228,11 @@ func (e *Execution) buildQuery() error {
 
 	var qualifies []string
 	if query.Qualify != nil {
-		qualifies = append(qualifies, sqlparser.Stringify(query.Qualify.X))
+		qualifies = append(qualifies, stringify(query.Qualify.X))
 	}
 
 	if query != e.query && e.query.Qualify != nil {
-		qualifies = append(qualifies, sqlparser.Stringify(e.query.Qualify))
+		qualifies = append(qualifies, stringify(e.query.Qualify))
 	}
 	if len(qualifies) > 0 {
 		builder.WriteString(" WHERE ")
@@ -198,7 +244,7 @@ func (e *Execution) buildQuery() error {
 
 	if len(e.query.OrderBy) > 0 {
 		builder.WriteString(" ORDER BY ")
-		builder.WriteString(sqlparser.Stringify(e.query.OrderBy))
+		builder.WriteString(stringify(e.query.OrderBy))
 	}
 	e.Parti = &PartiQL{
 		Query: builder.String(),
@@ -237,22 +283,8 @@ func (e *Execution) adjustQueryType(desc *types.TableDescription, rowType *Type,
 	attrTypes := buildAttributeTypes(desc)
 	for _, item := range query.List {
 		switch actual := item.Expr.(type) {
-		case *expr.Ident:
-			attrType, isRequired := attrTypes[actual.Name]
-			cName := item.Alias
-			if cName == "" {
-				cName = actual.Name
-			}
-			if !outerColumns.ShallOutput(cName) {
-				continue
-			}
-			_, column := rowType.Add(actual.Name, item.Alias, attrType, isRequired)
-			if outer, ok := outerColumns[cName]; ok {
-				column.DefaultValue = outer.DefaultValue
-			}
-
-		case *expr.Selector:
-			name := sqlparser.Stringify(actual)
+		case *expr.Ident, *expr.Selector:
+			name := attributePath(query, actual)
 			attrType, isRequired := attrTypes[name]
 			cName := item.Alias
 			if cName == "" {
@@ -266,15 +298,15 @@ func (e *Execution) adjustQueryType(desc *types.TableDescription, rowType *Type,
 				column.DefaultValue = outer.DefaultValue
 			}
 		case *expr.Call:
-			fName := strings.ToLower(sqlparser.Stringify(actual.X))
+			fName := strings.ToLower(stringify(actual.X))
 			if attrType, ok := attributeTypeCast[fName]; ok {
-				name := sqlparser.Stringify(actual.Args[0])
+				name := attributePath(query, actual.Args[0])
 				rowType.Add(name, item.Alias, attrType, false)
 				continue
 			}
 			newFunc := funcRegistry.Lookup(fName)
 			if newFunc == nil {
-				return fmt.Errorf("unknown function: %v", fName)
+				return fmt.Errorf("%w: unknown function: %v", ErrUnsupported, fName)
 			}
 			fn, fnType, err := newFunc(actual, rowType)
 			if err != nil {
@@ -284,12 +316,26 @@ func (e *Execution) adjustQueryType(desc *types.TableDescription, rowType *Type,
 			column.Type = fnType
 			column.Func = fn
 		default:
-			return fmt.Errorf("unsupported projection node %T", actual)
+			return fmt.Errorf("%w: projection node %T", ErrUnsupported, actual)
 		}
 	}
 	return nil
 }
 
+//attributePath returns document path of identifier node without table alias
+func attributePath(aQuery *query.Select, n node.Node) string {
+	name := stringify(n)
+	if alias := aQuery.From.Alias; alias != "" && strings.HasPrefix(name, alias+".") {
+		return name[len(alias)+1:]
+	}
+	return name
+}
+
+//stringify returns SQL fragment with document path list indexes restored
+func stringify(n node.Node) string {
+	return unescapeIndexes(sqlparser.Stringify(n))
+}
+
 func buildAttributeTypes(desc *types.TableDescription) map[string]string {
 	attrTypes := map[string]string{}
 	for _, attr := range desc.AttributeDefinitions {
@@ -299,12 +345,22 @@ func buildAttributeTypes(desc *types.TableDescription) map[string]string {
 }
 
 func (e *Execution) buildWildcardType(desc *types.TableDescription, sType *Type) {
+	attrTypes := buildAttributeTypes(desc)
+	for _, key := range desc.KeySchema {
+		field, column := sType.Add(*key.AttributeName, "", attrTypes[*key.AttributeName], true)
+		field.Type = WildcardType(attrTypes[*key.AttributeName])
+		column.Type = field.Type
+	}
 	for _, attr := range desc.AttributeDefinitions {
-		sType.Add(*attr.AttributeName, "", string(attr.AttributeType), true)
+		if _, ok := sType.Keys[*attr.AttributeName]; ok {
+			continue
+		}
+		sType.Field(*attr.AttributeName).Type = WildcardType(string(attr.AttributeType))
 	}
 }
 
 func (e *Execution) initInsert(desc *types.TableDescription) error {
+	e.insert = versionedInsert(e.insert, e.Version)
 	rowType := NewType(false)
 	attrTypes := buildAttributeTypes(desc)
 	e.Parti = &PartiQL{}
@@ -320,117 +376,286 @@ func (e *Execution) initInsert(desc *types.TableDescription) error {
 		builder.WriteString("'")
 		builder.WriteString(column)
 		builder.WriteString("':")
-		switch actual := e.insert.Values[i].Expr.(type) {
-		case *expr.Placeholder:
-			rowType.AddItem(NewPlaceholder(column))
-			rowType.numInput++
-			builder.WriteString("?")
-		case *expr.Literal:
-			builder.WriteString(actual.Value)
-		case *expr.Call:
-			fName := strings.ToLower(sqlparser.Stringify(actual.X))
-			switch fName {
-			case "strings", "array", "ints", "decimals":
-				builder.WriteString("<<")
-				for j, arg := range actual.Args {
-					if j > 0 {
-						builder.WriteString(",")
-					}
-					builder.WriteString(sqlparser.Stringify(arg))
-				}
-				builder.WriteString(">>")
-				continue
-			case "list":
-				builder.WriteString("[")
-				for j, arg := range actual.Args {
-					if j > 0 {
-						builder.WriteString(",")
-					}
-					builder.WriteString(sqlparser.Stringify(arg))
-				}
-				builder.WriteString("]")
-				continue
-			case "map":
-				args := actual.Raw[1 : len(actual.Raw)-1]
-				builder.WriteString(args)
-				continue
-			case "t":
-				args := actual.Raw[1 : len(actual.Raw)-1]
-				builder.WriteString(args)
-				continue
-			}
-			return fmt.Errorf("not supported: %T", actual)
-
-		default:
-			return fmt.Errorf("not supported: %T", actual)
+		value := e.insert.Values[i].Expr
+		if err := writeValue(&builder, value, column, rowType); err != nil {
+			return err
 		}
+		e.item = append(e.item, itemParameter(column, value, rowType))
 	}
 	if len(attrTypes) > 0 {
 		for k := range attrTypes {
-			return fmt.Errorf(k + " is required")
+			return fmt.Errorf("%w: %v is required", ErrInvalid, k)
 		}
 	}
 	builder.WriteString("}")
 	e.Parti = &PartiQL{Query: builder.String()}
 	e.Type = rowType
+	for _, key := range desc.KeySchema {
+		rowType.Keys[*key.AttributeName] = key.KeyType
+	}
+	if e.Returning != nil || e.Replace {
+		if err := e.checkItem(nil); err != nil {
+			return err
+		}
+	}
+	if err := e.initConflict(desc); err != nil {
+		return err
+	}
+	if err := e.initReturning(desc, ReturningAllNew, ReturningAllNew); err != nil {
+		return err
+	}
 	e.initState()
 	return nil
 }
 
+//checkItem checks that item (or supplied keys) values can be computed client side
+func (e *Execution) checkItem(keys map[string]types.KeyType) error {
+	for i, param := range e.item {
+		if param != nil {
+			continue
+		}
+		column := e.insert.Columns[i]
+		if _, ok := keys[column]; keys == nil || ok {
+			return fmt.Errorf("%w: %v value: %v, expected placeholder or literal", ErrUnsupported, column, stringify(e.insert.Values[i].Expr))
+		}
+	}
+	return nil
+}
+
+//itemParameter returns inserted attribute parameter, only placeholders and scalar literals are computed client side, otherwise nil
+func itemParameter(column string, value node.Node, rowType *Type) *Parameter {
+	switch actual := value.(type) {
+	case *expr.Placeholder:
+		return rowType.List[len(rowType.List)-1]
+	case *expr.Literal:
+		param := NewLiteral(actual.Value, actual.Kind)
+		if actual.Kind == "null" {
+			param.Value = nil
+		}
+		param.Name = column
+		return param
+	}
+	return nil
+}
+
+//Item returns inserted item
+func (e *Execution) Item(state *State) (map[string]types.AttributeValue, error) {
+	var result = make(map[string]types.AttributeValue, len(e.item))
+	for i, param := range e.item {
+		if param == nil {
+			return nil, fmt.Errorf("%w: %v value: %v", ErrUnsupported, e.insert.Columns[i], stringify(e.insert.Values[i].Expr))
+		}
+		value, err := itemValue(state, param)
+		if err != nil {
+			return nil, err
+		}
+		attrValue, err := Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to encode %v: %T(%v), %v", ErrInvalid, param.Name, value, value, err)
+		}
+		result[param.Name] = attrValue
+	}
+	return result, nil
+}
+
+//Key returns inserted item primary key
+func (e *Execution) Key(state *State) (map[string]types.AttributeValue, error) {
+	item, err := e.Item(state)
+	if err != nil {
+		return nil, err
+	}
+	var result = make(map[string]types.AttributeValue, len(e.Type.Keys))
+	for name := range e.Type.Keys {
+		value, ok := item[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing key %v value", ErrInvalid, name)
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+func itemValue(state *State, param *Parameter) (interface{}, error) {
+	if param.Kind != ParameterKindPlaceholder {
+		return param.Value, nil
+	}
+	if param.Pos >= len(state.Args) {
+		return nil, fmt.Errorf("%w: missing argument for parameter %v", ErrInvalid, param.Name)
+	}
+	return state.Args[param.Pos].Value, nil
+}
+
+//NumInput returns number of statement placeholders
+func (e *Execution) NumInput() int {
+	if len(e.Names) > 0 {
+		return e.NumNamed()
+	}
+	if e.Copy != nil && e.Copy.Query != nil { //COPY TO binds exported query parameters
+		return e.Copy.Query.NumInput()
+	}
+	result := e.Type.NumInput()
+	if e.Conflict != nil {
+		result += e.Conflict.args
+	}
+	return result
+}
+
+//writeValue writes PartiQL value, placeholders are registered as row type list parameters
+func writeValue(builder *strings.Builder, value node.Node, column string, rowType *Type) error {
+	switch actual := value.(type) {
+	case *expr.Placeholder:
+		rowType.AddItem(NewPlaceholder(column))
+		builder.WriteString("?")
+	case *expr.Literal:
+		builder.WriteString(actual.Value)
+	case *expr.Call:
+		fName := strings.ToLower(stringify(actual.X))
+		switch fName {
+		case "strings", "array", "ints", "decimals":
+			builder.WriteString("<<")
+			for j, arg := range actual.Args {
+				if j > 0 {
+					builder.WriteString(",")
+				}
+				builder.WriteString(stringify(arg))
+			}
+			builder.WriteString(">>")
+			return nil
+		case "list":
+			builder.WriteString("[")
+			for j, arg := range actual.Args {
+				if j > 0 {
+					builder.WriteString(",")
+				}
+				builder.WriteString(stringify(arg))
+			}
+			builder.WriteString("]")
+			return nil
+		case "map":
+			args := actual.Raw[1 : len(actual.Raw)-1]
+			builder.WriteString(args)
+			return nil
+		case "t":
+			args := actual.Raw[1 : len(actual.Raw)-1]
+			builder.WriteString(args)
+			return nil
+		}
+		return writeExpr(builder, actual, column, rowType)
+	case *expr.Binary, *expr.Unary, *expr.Ident, *expr.Selector, *expr.Parenthesis:
+		return writeExpr(builder, actual, column, rowType)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupported, actual)
+	}
+	return nil
+}
+
+//writeExpr writes native PartiQL expression i.e. Count + ?, list_append(Tags, ?)
+func writeExpr(builder *strings.Builder, value node.Node, column string, rowType *Type) error {
+	if call, ok := value.(*expr.Call); ok {
+		fName := strings.ToLower(stringify(call.X))
+		if !nativeFunctions[fName] {
+			return fmt.Errorf("%w: function %v", ErrUnsupported, fName)
+		}
+	}
+	placeholders(value, func(placeholder *expr.Placeholder) {
+		rowType.AddItem(NewPlaceholder(column))
+	})
+	builder.WriteString(stringify(value))
+	return nil
+}
+
 func (e *Execution) initUpdate(desc *types.TableDescription) error {
-	if e.query.Qualify == nil {
-		return fmt.Errorf("where clause is required")
+	if e.update.Query == nil || e.update.Query.Qualify == nil {
+		return fmt.Errorf("%w: where clause is required", ErrInvalid)
+	}
+	e.query = e.update.Query
+	if err := e.initVersion(e.update.Version); err != nil {
+		return err
+	}
+	if e.update.Version != "" {
+		increment, err := e.update.versionIncrement()
+		if err != nil {
+			return err
+		}
+		if increment != nil {
+			e.update.Set = append(e.update.Set, increment)
+		}
 	}
 	rowType := NewType(false)
+	e.Type = rowType
 	e.Parti = &PartiQL{}
 	builder := strings.Builder{}
 	builder.WriteString("UPDATE ")
 	builder.WriteString(*desc.TableName)
-	builder.WriteString("\n")
-	for i, column := range e.insert.Columns {
-		if i > 0 {
-			builder.WriteString(",")
-		}
-		builder.WriteString("SET ")
-		builder.WriteString(column)
-		builder.WriteString("=")
-		switch actual := e.insert.Values[i].Expr.(type) {
-		case *expr.Placeholder:
-			rowType.AddItem(NewPlaceholder(column))
-			rowType.numInput++
-			builder.WriteString("?")
-		case *expr.Literal:
-			builder.WriteString(actual.Value)
+	for _, assignment := range e.update.Set {
+		builder.WriteString(" SET ")
+		builder.WriteString(assignment.Path)
+		builder.WriteString(" = ")
+		switch assignment.Kind {
+		case AssignmentAdd, AssignmentDelete:
+			fName := "set_add("
+			if assignment.Kind == AssignmentDelete {
+				fName = "set_delete("
+			}
+			builder.WriteString(fName)
+			builder.WriteString(assignment.Path)
+			builder.WriteString(", ")
+			if err := writeValue(&builder, assignment.Expr, assignment.Path, rowType); err != nil {
+				return err
+			}
+			builder.WriteString(")")
+		default:
+			if err := writeValue(&builder, assignment.Expr, assignment.Path, rowType); err != nil {
+				return err
+			}
 		}
 	}
+	for _, path := range e.update.Remove {
+		builder.WriteString(" REMOVE ")
+		builder.WriteString(path)
+	}
 	builder.WriteString(" WHERE ")
-	builder.WriteString(sqlparser.Stringify(e.query.Qualify.X))
+	builder.WriteString(stringify(e.query.Qualify.X))
 	if err := e.initCriteria(); err != nil {
 		return err
 	}
+	if err := e.initReturning(desc, ReturningAllNew, returningModes...); err != nil {
+		return err
+	}
+	if e.Returning != nil {
+		builder.WriteString(" RETURNING " + e.Returning.Mode + " *")
+	}
 	e.Parti = &PartiQL{Query: builder.String()}
-	e.Type = rowType
 	e.initState()
 	return nil
 }
 
-func (e *Execution) initDelete(desc *types.TableDescription) error {
-	if e.query.Qualify == nil {
-		return fmt.Errorf("where clause is required")
+func (e *Execution) initDelete(desc *types.TableDescription, version string) error {
+	if e.delete.Qualify == nil {
+		return fmt.Errorf("%w: where clause is required", ErrInvalid)
 	}
-	rowType := NewType(false)
+	e.query = &query.Select{Qualify: e.delete.Qualify}
+	e.query.From.Alias = e.delete.Target.Alias
+	if err := e.initVersion(version); err != nil {
+		return err
+	}
+	e.Type = NewType(false)
 	e.Parti = &PartiQL{}
 	builder := strings.Builder{}
 	builder.WriteString("DELETE FROM ")
 	builder.WriteString(*desc.TableName)
-	builder.WriteString("\n")
 	builder.WriteString(" WHERE ")
-	builder.WriteString(sqlparser.Stringify(e.query.Qualify.X))
+	builder.WriteString(stringify(e.query.Qualify.X))
 	if err := e.initCriteria(); err != nil {
 		return err
 	}
+	if err := e.initReturning(desc, ReturningAllOld, ReturningAllOld); err != nil {
+		return err
+	}
+	if e.Returning != nil {
+		builder.WriteString(" RETURNING " + e.Returning.Mode + " *")
+	}
 	e.Parti = &PartiQL{Query: builder.String()}
-	e.Type = rowType
 	e.initState()
 	return nil
 }
@@ -481,6 +706,23 @@ func (e *Execution) NewState(args []driver.NamedValue) *State {
 	return result
 }
 
+//NewQueryState creates a query state, query states share type resolved by the first completed query,
+//until then and for wildcard query each state discovers attributes with its own type copy
+func (e *Execution) NewQueryState(args []driver.NamedValue) *State {
+	if e.Type.Wildcard {
+		return NewState(e.Type.Clone(), args)
+	}
+	resolved := e.queryType.Load()
+	if resolved == nil {
+		return NewState(e.Type.Clone(), args)
+	}
+	if state, ok := e.queryStates.Get().(*State); ok {
+		state.Args = args
+		return state
+	}
+	return NewState(resolved, args)
+}
+
 //NewQuery creates an query execution
 func NewQuery(table string, query *query.Select, desc *types.TableDescription) (*Execution, error) {
 	result := &Execution{
@@ -502,16 +744,26 @@ func NewQuery(table string, query *query.Select, desc *types.TableDescription) (
 }
 
 func (e *Execution) initState() {
-	e.state.New = func() interface{} {
+	e.state = &sync.Pool{New: func() interface{} {
 		return NewState(e.Type, nil)
-	}
+	}}
 }
 
 //NewInsert creates an insert execution
-func NewInsert(table string, stmt *insert.Statement, desc *types.TableDescription) (*Execution, error) {
+func NewInsert(table string, stmt *insert.Statement, returning *Returning, desc *types.TableDescription) (*Execution, error) {
+	return NewUpsert(table, stmt, returning, nil, false, "", desc)
+}
+
+//NewUpsert creates an insert execution with ON CONFLICT clause or REPLACE semantics, versioned item starts with version 1 unless inserted explicitly
+func NewUpsert(table string, stmt *insert.Statement, returning *Returning, conflict *Conflict, replace bool, version string, desc *types.TableDescription) (*Execution, error) {
 	result := &Execution{
-		Table:  table,
-		insert: stmt,
+		Kind:      KindInsert,
+		Table:     table,
+		insert:    stmt,
+		Returning: returning,
+		Conflict:  conflict,
+		Replace:   replace,
+		Version:   version,
 	}
 	if err := result.initInsert(desc); err != nil {
 		return nil, err
@@ -520,10 +772,12 @@ func NewInsert(table string, stmt *insert.Statement, desc *types.TableDescriptio
 }
 
 //NewUpdate creates an update execution
-func NewUpdate(table string, stmt *insert.Statement, desc *types.TableDescription) (*Execution, error) {
+func NewUpdate(table string, stmt *Update, desc *types.TableDescription) (*Execution, error) {
 	result := &Execution{
-		Table:  table,
-		insert: stmt,
+		Kind:      KindUpdate,
+		Table:     table,
+		update:    stmt,
+		Returning: stmt.Returning,
 	}
 	if err := result.initUpdate(desc); err != nil {
 		return nil, err
@@ -533,12 +787,14 @@ func NewUpdate(table string, stmt *insert.Statement, desc *types.TableDescriptio
 }
 
 //NewDelete creates an update execution
-func NewDelete(table string, stmt *del.Statement, desc *types.TableDescription) (*Execution, error) {
+func NewDelete(table string, stmt *del.Statement, returning *Returning, version string, desc *types.TableDescription) (*Execution, error) {
 	result := &Execution{
-		Table:  table,
-		delete: stmt,
+		Kind:      KindDelete,
+		Table:     table,
+		delete:    stmt,
+		Returning: returning,
 	}
-	if err := result.initDelete(desc); err != nil {
+	if err := result.initDelete(desc, version); err != nil {
 		return nil, err
 	}
 	return result, nil
@@ -0,0 +1,497 @@
+package exec_test
+
+import (
+	"database/sql/driver"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/francoispqt/gojay"
+	"github.com/stretchr/testify/assert"
+	ndynamodb "github.com/viant/dyndb/internal/dynamodb"
+	"github.com/viant/dyndb/internal/exec"
+	"github.com/viant/sqlparser"
+	del "github.com/viant/sqlparser/delete"
+	"github.com/viant/sqlparser/insert"
+	"strings"
+	"testing"
+)
+
+func TestNewQuery(t *testing.T) {
+	table := "Publication"
+	desc := &types.TableDescription{
+		TableName: &table,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: stringPtr("ISBN"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: stringPtr("ISBN"), KeyType: types.KeyTypeHash},
+		},
+	}
+
+	var testCases = []struct {
+		description string
+		SQL         string
+		expectQL    string
+		items       []string
+		expectCols  []string
+		expect      [][]driver.Value
+	}{
+		{
+			description: "nested map path",
+			SQL:         "SELECT ISBN, Info.Address.City FROM Publication WHERE Info.Address.Zip = ?",
+			expectQL:    "SELECT ISBN, Info.Address.City FROM Publication WHERE Info.Address.Zip = ?",
+			items:       []string{`{"ISBN":{"S":"AAA"},"Info":{"M":{"Address":{"M":{"City":{"S":"Seattle"}}}}}}`},
+			expect:      [][]driver.Value{{"AAA", "Seattle"}},
+		},
+		{
+			description: "colliding leaf names",
+			SQL:         "SELECT a.id, b.id FROM Publication",
+			expectQL:    "SELECT a.id, b.id FROM Publication",
+			items:       []string{`{"a":{"M":{"id":{"S":"1"}}},"b":{"M":{"id":{"S":"2"}}}}`},
+			expect:      [][]driver.Value{{"1", "2"}},
+		},
+		{
+			description: "list indexes",
+			SQL:         "SELECT ISBN, Tags[1], Tags[3] AS Last FROM Publication t WHERE Tags[0] = 'x'",
+			expectQL:    "SELECT ISBN, Tags[1], Tags[3] FROM Publication WHERE Tags[0] = 'x'",
+			items:       []string{`{"ISBN":{"S":"AAA"},"Tags":{"L":[{"S":"b"},{"S":"d"}]}}`},
+			expect:      [][]driver.Value{{"AAA", "b", "d"}},
+		},
+		{
+			description: "whole attribute with nested path",
+			SQL:         "SELECT t.Info, Info.Codes[1] FROM Publication t",
+			expectQL:    "SELECT Info, Info.Codes[1] FROM Publication",
+			items:       []string{`{"Info":{"M":{"Codes":{"L":[{"N":"1"},{"N":"2"}]}}}}`},
+			expect:      [][]driver.Value{{map[string]interface{}{"Codes": []interface{}{1, 2}}, 2}},
+		},
+		{
+			description: "wildcard with schemaless attributes",
+			SQL:         "SELECT * FROM Publication",
+			expectQL:    "SELECT * FROM Publication",
+			items: []string{
+				`{"Price":{"N":"10"},"Name":{"S":"Title 1"},"ISBN":{"S":"AAA"}}`,
+				`{"ISBN":{"S":"BBB"},"Price":{"N":"10.5"},"Active":{"BOOL":true},"Name":{"NULL":true}}`,
+			},
+			expectCols: []string{"ISBN", "Active", "Name", "Price"},
+			expect: [][]driver.Value{
+				{"AAA", nil, "Title 1", 10.0},
+				{"BBB", true, nil, 10.5},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		aQuery, err := sqlparser.ParseQuery(exec.EscapeIndexes(testCase.SQL))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		execution, err := exec.NewQuery(table, aQuery, desc)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectQL, execution.Parti.Query, testCase.description)
+		state := execution.NewQueryState(nil)
+		output := ndynamodb.NewExecuteStatementOutput(state.Type)
+		output.Data = []byte(`{"Items":[` + strings.Join(testCase.items, ",") + `]}`)
+		if err = gojay.Unmarshal(output.Data, output); !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if !assert.Nil(t, state.Init(), testCase.description) {
+			continue
+		}
+		if len(testCase.expectCols) > 0 {
+			var columns []string
+			for _, column := range state.Type.Columns {
+				columns = append(columns, column.Name)
+			}
+			assert.EqualValues(t, testCase.expectCols, columns, testCase.description)
+		}
+		var actual [][]driver.Value
+		for _, row := range output.Rows {
+			values := make([]driver.Value, len(state.Type.Columns))
+			state.SetDest(values)
+			err = gojay.Unmarshal(output.Data[row.Begin:row.End], state)
+			if err == nil {
+				err = state.Reconcile()
+			}
+			if !assert.Nil(t, err, testCase.description) {
+				break
+			}
+			actual = append(actual, values)
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
+
+func TestNewUpdate(t *testing.T) {
+	table := "Publication"
+	desc := &types.TableDescription{
+		TableName: &table,
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: stringPtr("ISBN"), KeyType: types.KeyTypeHash},
+		},
+	}
+	var testCases = []struct {
+		description string
+		SQL         string
+		expectQL    string
+		expectInput int
+		version     string
+		hasError    bool
+	}{
+		{
+			description: "multi assignment set",
+			SQL:         "UPDATE Publication SET Name = ?, Price = 10.5 WHERE ISBN = ?",
+			expectQL:    "UPDATE Publication SET Name = ? SET Price = 10.5 WHERE ISBN = ?",
+			expectInput: 2,
+		},
+		{
+			description: "arithmetic and list append",
+			SQL:         "UPDATE Publication SET Views = Views + ?, Tags = list_append(Tags, ?) WHERE ISBN = ?",
+			expectQL:    "UPDATE Publication SET Views = Views + ? SET Tags = list_append(Tags, ?) WHERE ISBN = ?",
+			expectInput: 3,
+		},
+		{
+			description: "nested path set and remove",
+			SQL:         "UPDATE Publication SET Info.Address.City = ? REMOVE Tags[1], Info.Zip WHERE ISBN = 'AAA'",
+			expectQL:    "UPDATE Publication SET Info.Address.City = ? REMOVE Tags[1] REMOVE Info.Zip WHERE ISBN = 'AAA'",
+			expectInput: 1,
+		},
+		{
+			description: "set add and delete",
+			SQL:         "UPDATE Publication ADD Labels ? DELETE Codes ? WHERE ISBN = ?",
+			expectQL:    "UPDATE Publication SET Labels = set_add(Labels, ?) SET Codes = set_delete(Codes, ?) WHERE ISBN = ?",
+			expectInput: 3,
+		},
+		{
+			description: "returning all new",
+			SQL:         "UPDATE Publication SET Views = Views + 1 WHERE ISBN = ? RETURNING *",
+			expectQL:    "UPDATE Publication SET Views = Views + 1 WHERE ISBN = ? RETURNING ALL NEW *",
+			expectInput: 1,
+		},
+		{
+			description: "returning modified old columns",
+			SQL:         "UPDATE Publication SET Views = ? WHERE ISBN = ? RETURNING MODIFIED OLD Views",
+			expectQL:    "UPDATE Publication SET Views = ? WHERE ISBN = ? RETURNING MODIFIED OLD *",
+			expectInput: 2,
+		},
+		{
+			description: "version condition and increment",
+			SQL:         "UPDATE Publication SET Name = ? WHERE ISBN = ? OR ISBN = ?",
+			expectQL:    "UPDATE Publication SET Name = ? SET Version = Version + 1 WHERE (ISBN = ? OR ISBN = ?) AND Version = ?",
+			expectInput: 4,
+			version:     "Version",
+		},
+		{
+			description: "explicit version condition and assignment",
+			SQL:         "UPDATE Publication SET Version = ? WHERE ISBN = ? AND Version = ?",
+			expectQL:    "UPDATE Publication SET Version = ? WHERE ISBN = ? AND Version = ?",
+			expectInput: 3,
+			version:     "Version",
+		},
+		{
+			description: "lower case remove before set",
+			SQL:         "update Publication remove Info.Zip set Price = (Price - ?) * 2 where ISBN = ?",
+			expectQL:    "UPDATE Publication SET Price = (Price - ?) * 2 REMOVE Info.Zip WHERE ISBN = ?",
+			expectInput: 2,
+		},
+		{
+			description: "missing where",
+			SQL:         "UPDATE Publication SET Name = ?",
+			hasError:    true,
+		},
+		{
+			description: "missing set",
+			SQL:         "UPDATE Publication WHERE ISBN = ?",
+			hasError:    true,
+		},
+		{
+			description: "unexpected trailing clause",
+			SQL:         "UPDATE Publication SET Name = ? WHERE ISBN = ? LIMIT 1",
+			hasError:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		update, err := exec.ParseUpdate(exec.EscapeIndexes(testCase.SQL))
+		if err == nil {
+			update.Version = testCase.version
+			var execution *exec.Execution
+			if execution, err = exec.NewUpdate(table, update, desc); err == nil {
+				assert.EqualValues(t, testCase.expectQL, execution.Parti.Query, testCase.description)
+				assert.EqualValues(t, testCase.expectInput, execution.Type.NumInput(), testCase.description)
+			}
+		}
+		assert.EqualValues(t, testCase.hasError, err != nil, testCase.description)
+	}
+}
+
+func TestExecution_Returning(t *testing.T) {
+	table := "Publication"
+	desc := &types.TableDescription{
+		TableName: &table,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: stringPtr("ISBN"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: stringPtr("ISBN"), KeyType: types.KeyTypeHash},
+		},
+	}
+	var testCases = []struct {
+		description string
+		SQL         string
+		args        []driver.NamedValue
+		expectQL    string
+		item        string
+		expectCols  []string
+		expect      []driver.Value
+		hasError    bool
+	}{
+		{
+			description: "delete returning columns",
+			SQL:         "DELETE FROM Publication WHERE ISBN = ? RETURNING Name, Info.City",
+			expectQL:    "DELETE FROM Publication WHERE ISBN = ? RETURNING ALL OLD *",
+			item:        `{"ISBN":{"S":"AAA"},"Name":{"S":"Title 1"},"Info":{"M":{"City":{"S":"Seattle"}}},"Price":{"N":"10"}}`,
+			expectCols:  []string{"Name", "Info.City"},
+			expect:      []driver.Value{"Title 1", "Seattle"},
+		},
+		{
+			description: "delete returning new",
+			SQL:         "DELETE FROM Publication WHERE ISBN = ? RETURNING ALL NEW *",
+			hasError:    true,
+		},
+		{
+			description: "insert returning",
+			SQL:         "INSERT INTO Publication(ISBN, Name, Price) VALUES(?, 'Title 1', ?) RETURNING *",
+			args:        []driver.NamedValue{{Ordinal: 1, Value: "AAA"}, {Ordinal: 2, Value: 10}},
+			expectQL:    "INSERT INTO Publication VALUE {'ISBN':?,'Name':'Title 1','Price':?}",
+			expectCols:  []string{"ISBN", "Name", "Price"},
+			expect:      []driver.Value{"AAA", "Title 1", 10.0},
+		},
+	}
+
+	for _, testCase := range testCases {
+		SQL, returning, err := exec.ParseReturning(testCase.SQL)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var execution *exec.Execution
+		if strings.HasPrefix(SQL, "DELETE") {
+			var stmt *del.Statement
+			if stmt, err = sqlparser.ParseDelete(SQL); err == nil {
+				execution, err = exec.NewDelete(table, stmt, returning, "", desc)
+			}
+		} else {
+			var stmt *insert.Statement
+			if stmt, err = sqlparser.ParseInsert(SQL); err == nil {
+				execution, err = exec.NewInsert(table, stmt, returning, desc)
+			}
+		}
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectQL, execution.Parti.Query, testCase.description)
+		state := execution.NewQueryState(testCase.args)
+		output := ndynamodb.NewExecuteStatementOutput(state.Type)
+		if testCase.item != "" {
+			output.Data = []byte(`{"Items":[` + testCase.item + `]}`)
+			err = gojay.Unmarshal(output.Data, output)
+		} else {
+			item, err := execution.Item(state)
+			if !assert.Nil(t, err, testCase.description) {
+				continue
+			}
+			err = output.Load(item)
+		}
+		if !assert.Nil(t, err, testCase.description) || !assert.Nil(t, state.Init(), testCase.description) {
+			continue
+		}
+		var columns []string
+		for _, column := range state.Type.Columns {
+			columns = append(columns, column.Name)
+		}
+		assert.EqualValues(t, testCase.expectCols, columns, testCase.description)
+		if !assert.EqualValues(t, 1, len(output.Rows), testCase.description) {
+			continue
+		}
+		values := make([]driver.Value, len(state.Type.Columns))
+		state.SetDest(values)
+		err = gojay.Unmarshal(output.Data[output.Rows[0].Begin:output.Rows[0].End], state)
+		if err == nil {
+			err = state.Reconcile()
+		}
+		assert.Nil(t, err, testCase.description)
+		assert.EqualValues(t, testCase.expect, values, testCase.description)
+	}
+}
+
+func TestExecution_NewQueryState(t *testing.T) {
+	table := "Publication"
+	desc := &types.TableDescription{
+		TableName:            &table,
+		AttributeDefinitions: []types.AttributeDefinition{{AttributeName: stringPtr("ISBN"), AttributeType: types.ScalarAttributeTypeS}},
+		KeySchema:            []types.KeySchemaElement{{AttributeName: stringPtr("ISBN"), KeyType: types.KeyTypeHash}},
+	}
+	var testCases = []struct {
+		description  string
+		SQL          string
+		item         string
+		path         string
+		expectShared bool
+	}{
+		{
+			description:  "resolved type shared",
+			SQL:          "SELECT ISBN, Info.Title FROM Publication",
+			item:         `{"ISBN":{"S":"AAA"},"Info":{"M":{"Title":{"S":"Go"}}}}`,
+			path:         "Info",
+			expectShared: true,
+		},
+		{
+			description: "wildcard type copied",
+			SQL:         "SELECT * FROM Publication",
+			item:        `{"ISBN":{"S":"AAA"},"Price":{"N":"10"}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		aQuery, err := sqlparser.ParseQuery(exec.EscapeIndexes(testCase.SQL))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		execution, err := exec.NewQuery(table, aQuery, desc)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		first := execution.NewQueryState(nil)
+		assert.True(t, first.Type != execution.Type, testCase.description)
+		if testCase.path != "" {
+			assert.True(t, first.Type.Path(testCase.path) != execution.Type.Path(testCase.path), testCase.description)
+		}
+		unresolved := execution.NewQueryState(nil)
+		assert.True(t, unresolved.Type != first.Type, testCase.description)
+		output := ndynamodb.NewExecuteStatementOutput(first.Type)
+		if err = gojay.Unmarshal([]byte(`{"Items":[`+testCase.item+`]}`), output); !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if !assert.Nil(t, first.Init(), testCase.description) {
+			continue
+		}
+		execution.ReleaseState(first)
+		execution.ReleaseState(unresolved)
+		second := execution.NewQueryState(nil)
+		assert.EqualValues(t, testCase.expectShared, second.Type == first.Type, testCase.description)
+	}
+}
+
+func TestNewUpsert(t *testing.T) {
+	table := "Publication"
+	desc := &types.TableDescription{
+		TableName: &table,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: stringPtr("ISBN"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: stringPtr("ISBN"), KeyType: types.KeyTypeHash},
+		},
+	}
+	var testCases = []struct {
+		description    string
+		SQL            string
+		args           []driver.NamedValue
+		expectQL       string
+		expectConflict string
+		expectInput    int
+		expectArgs     []interface{}
+		version        string
+		doNothing      bool
+		hasError       bool
+	}{
+		{
+			description: "do nothing",
+			SQL:         "INSERT INTO Publication(ISBN, Name) VALUES(?, ?) ON CONFLICT DO NOTHING",
+			expectQL:    "INSERT INTO Publication VALUE {'ISBN':?,'Name':?}",
+			expectInput: 2,
+			doNothing:   true,
+		},
+		{
+			description:    "do update",
+			SQL:            "INSERT INTO Publication(ISBN, Views) VALUES(?, 1) ON CONFLICT (ISBN) DO UPDATE SET Views = Views + ?, Name = ?",
+			args:           []driver.NamedValue{{Ordinal: 1, Value: "AAA"}, {Ordinal: 2, Value: 2}, {Ordinal: 3, Value: "Title"}},
+			expectQL:       "INSERT INTO Publication VALUE {'ISBN':?,'Views':1}",
+			expectConflict: "UPDATE Publication SET Views = Views + ? SET Name = ? WHERE ISBN = ?",
+			expectInput:    3,
+			expectArgs:     []interface{}{2, "Title", "AAA"},
+		},
+		{
+			description: "versioned insert",
+			SQL:         "INSERT INTO Publication(ISBN, Name) VALUES(?, ?) ON CONFLICT DO NOTHING",
+			expectQL:    "INSERT INTO Publication VALUE {'ISBN':?,'Name':?,'Version':1}",
+			expectInput: 2,
+			version:     "Version",
+			doNothing:   true,
+		},
+		{
+			description: "versioned insert with explicit version",
+			SQL:         "INSERT INTO Publication(ISBN, Version) VALUES(?, ?) ON CONFLICT DO NOTHING",
+			expectQL:    "INSERT INTO Publication VALUE {'ISBN':?,'Version':?}",
+			expectInput: 2,
+			version:     "Version",
+			doNothing:   true,
+		},
+		{
+			description:    "versioned do update",
+			SQL:            "INSERT INTO Publication(ISBN, Views) VALUES(?, 1) ON CONFLICT (ISBN) DO UPDATE SET Views = Views + ?",
+			args:           []driver.NamedValue{{Ordinal: 1, Value: "AAA"}, {Ordinal: 2, Value: 2}},
+			expectQL:       "INSERT INTO Publication VALUE {'ISBN':?,'Views':1,'Version':1}",
+			expectConflict: "UPDATE Publication SET Views = Views + ? SET Version = Version + 1 WHERE ISBN = ?",
+			expectInput:    2,
+			expectArgs:     []interface{}{2, "AAA"},
+			version:        "Version",
+		},
+		{
+			description: "invalid action",
+			SQL:         "INSERT INTO Publication(ISBN) VALUES(?) ON CONFLICT DO REPLACE",
+			hasError:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		SQL, conflict, err := exec.ParseConflict(testCase.SQL)
+		var execution *exec.Execution
+		if err == nil {
+			var stmt *insert.Statement
+			if stmt, err = sqlparser.ParseInsert(SQL); err == nil {
+				execution, err = exec.NewUpsert(table, stmt, nil, conflict, false, testCase.version, desc)
+			}
+		}
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectQL, execution.Parti.Query, testCase.description)
+		assert.EqualValues(t, testCase.expectInput, execution.NumInput(), testCase.description)
+		assert.EqualValues(t, testCase.doNothing, execution.Conflict.DoNothing, testCase.description)
+		if testCase.doNothing {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectConflict, execution.Conflict.Update.Parti.Query, testCase.description)
+		args, err := execution.ConflictArgs(execution.NewState(testCase.args))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []interface{}
+		for _, arg := range args {
+			actual = append(actual, arg.Value)
+		}
+		assert.EqualValues(t, testCase.expectArgs, actual, testCase.description)
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
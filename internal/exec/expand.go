@@ -0,0 +1,162 @@
+package exec
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"strings"
+)
+
+//MaxInValues defines max number of values sent in a single PartiQL IN list
+const MaxInValues = 50
+
+//Request represents PartiQL statement with parameters
+type Request struct {
+	Query      string
+	Parameters []types.AttributeValue
+}
+
+//Requests returns PartiQL requests for list followed by criteria parameters,
+//a slice bound to a sole IN list placeholder is expanded to one parameter per element,
+//a list exceeding MaxInValues is split into several requests, an empty list returns no request
+func (s *State) Requests(query string) ([]*Request, error) {
+	var values [][]types.AttributeValue //values by placeholder
+	expanded := false
+	split := -1
+	for _, params := range [][]*Parameter{s.Type.List, s.Type.Criteria} {
+		for _, param := range params {
+			if param.Kind != ParameterKindPlaceholder {
+				continue
+			}
+			if param.InList && param.Pos < len(s.Args) && isListArg(s.Args[param.Pos].Value) {
+				list, err := encodeList(s.Args[param.Pos].Value)
+				if err != nil {
+					return nil, err
+				}
+				if len(list) == 0 {
+					return nil, nil
+				}
+				if len(list) > MaxInValues {
+					if split != -1 {
+						return nil, fmt.Errorf("%w: only one IN list can exceed %v values", ErrUnsupported, MaxInValues)
+					}
+					split = len(values)
+				}
+				values = append(values, list)
+				expanded = true
+				continue
+			}
+			attrValue, err := s.encodeParameter(param)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, []types.AttributeValue{attrValue})
+		}
+	}
+	if !expanded {
+		request := &Request{Query: query}
+		for _, value := range values {
+			request.Parameters = append(request.Parameters, value...)
+		}
+		return []*Request{request}, nil
+	}
+	chunks := [][]types.AttributeValue{nil}
+	if split != -1 {
+		chunks = chunks[:0]
+		for list := values[split]; len(list) > 0; {
+			size := MaxInValues
+			if size > len(list) {
+				size = len(list)
+			}
+			chunks = append(chunks, list[:size])
+			list = list[size:]
+		}
+	}
+	var result = make([]*Request, 0, len(chunks))
+	counts := make([]int, len(values))
+	for _, chunk := range chunks {
+		request := &Request{}
+		for i, value := range values {
+			if i == split {
+				value = chunk
+			}
+			counts[i] = len(value)
+			request.Parameters = append(request.Parameters, value...)
+		}
+		var err error
+		if request.Query, err = expandPlaceholders(query, counts); err != nil {
+			return nil, err
+		}
+		result = append(result, request)
+	}
+	return result, nil
+}
+
+//isListArg returns true if argument is a slice or array other than binary value
+func isListArg(arg interface{}) bool {
+	if arg == nil {
+		return false
+	}
+	rType := reflect.TypeOf(arg)
+	switch rType.Kind() {
+	case reflect.Slice, reflect.Array:
+		return rType.Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+func encodeList(arg interface{}) ([]types.AttributeValue, error) {
+	value := reflect.ValueOf(arg)
+	var result = make([]types.AttributeValue, value.Len())
+	for i := range result {
+		item, err := NormalizeArg(value.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("%w: IN list item[%v]: %v", ErrInvalid, i, err)
+		}
+		attrValue, err := Encode(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to encode IN list item[%v]: %T(%v), %v", ErrInvalid, i, item, item, err)
+		}
+		result[i] = attrValue
+	}
+	return result, nil
+}
+
+//expandPlaceholders replaces n-th placeholder outside of literals and quoted identifiers with counts[n] comma separated placeholders
+func expandPlaceholders(query string, counts []int) (string, error) {
+	var result strings.Builder
+	index := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch c {
+		case '\'', '"':
+			end := strings.IndexByte(query[i+1:], c)
+			if end == -1 {
+				result.WriteString(query[i:])
+				i = len(query)
+				continue
+			}
+			end += i + 1
+			result.WriteString(query[i : end+1])
+			i = end
+			continue
+		case '?':
+			if index >= len(counts) {
+				return "", fmt.Errorf("%w: placeholders count mismatch: %v", ErrInvalid, query)
+			}
+			for j := 0; j < counts[index]; j++ {
+				if j > 0 {
+					result.WriteString(", ")
+				}
+				result.WriteByte('?')
+			}
+			index++
+			continue
+		}
+		result.WriteByte(c)
+	}
+	if index != len(counts) {
+		return "", fmt.Errorf("%w: placeholders count mismatch: %v", ErrInvalid, query)
+	}
+	return result.String(), nil
+}
@@ -0,0 +1,106 @@
+package exec_test
+
+import (
+	"database/sql/driver"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dyndb/internal/exec"
+	"github.com/viant/sqlparser"
+	"strings"
+	"testing"
+)
+
+func TestState_Requests(t *testing.T) {
+	table := "Publication"
+	desc := &types.TableDescription{
+		TableName: &table,
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: stringPtr("ISBN"), KeyType: types.KeyTypeHash},
+		},
+	}
+	var isbns []string
+	for i := 0; i < exec.MaxInValues+2; i++ {
+		isbns = append(isbns, "isbn")
+	}
+	var testCases = []struct {
+		description string
+		SQL         string
+		args        []interface{}
+		expectQL    []string
+		expectLen   []int
+		hasError    bool
+	}{
+		{
+			description: "scalar argument",
+			SQL:         "SELECT * FROM Publication WHERE ISBN IN (?)",
+			args:        []interface{}{"1"},
+			expectQL:    []string{"SELECT * FROM Publication WHERE ISBN IN (?)"},
+			expectLen:   []int{1},
+		},
+		{
+			description: "slice argument",
+			SQL:         "SELECT * FROM Publication WHERE Name = ? AND ISBN IN (?) AND Price > ?",
+			args:        []interface{}{"Title '?'", []int{1, 2, 3}, 10},
+			expectQL:    []string{"SELECT * FROM Publication WHERE Name = ? AND ISBN IN (?, ?, ?) AND Price > ?"},
+			expectLen:   []int{5},
+		},
+		{
+			description: "list split",
+			SQL:         "SELECT * FROM Publication WHERE ISBN IN (?) AND Price > ?",
+			args:        []interface{}{isbns, 10},
+			expectQL: []string{
+				"SELECT * FROM Publication WHERE ISBN IN (" + strings.Repeat("?, ", exec.MaxInValues-1) + "?) AND Price > ?",
+				"SELECT * FROM Publication WHERE ISBN IN (?, ?) AND Price > ?",
+			},
+			expectLen: []int{exec.MaxInValues + 1, 3},
+		},
+		{
+			description: "empty list",
+			SQL:         "SELECT * FROM Publication WHERE ISBN IN (?)",
+			args:        []interface{}{[]string{}},
+		},
+		{
+			description: "multiple placeholders list is not expanded",
+			SQL:         "SELECT * FROM Publication WHERE ISBN IN (?, ?)",
+			args:        []interface{}{[]string{"1"}, "2"},
+			expectQL:    []string{"SELECT * FROM Publication WHERE ISBN IN (?, ?)"},
+			expectLen:   []int{2},
+		},
+		{
+			description: "two split lists",
+			SQL:         "SELECT * FROM Publication WHERE ISBN IN (?) AND Name IN (?)",
+			args:        []interface{}{isbns, isbns},
+			hasError:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		aQuery, err := sqlparser.ParseQuery(exec.EscapeIndexes(testCase.SQL))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		execution, err := exec.NewQuery(table, aQuery, desc)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var args []driver.NamedValue
+		for i, arg := range testCase.args {
+			args = append(args, driver.NamedValue{Ordinal: i + 1, Value: arg})
+		}
+		requests, err := execution.NewQueryState(args).Requests(execution.Parti.Query)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if !assert.EqualValues(t, len(testCase.expectQL), len(requests), testCase.description) {
+			continue
+		}
+		for i, request := range requests {
+			assert.EqualValues(t, testCase.expectQL[i], request.Query, testCase.description)
+			assert.EqualValues(t, testCase.expectLen[i], len(request.Parameters), testCase.description)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package exec
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	//AccessQuery represents key condition access, partition key equality limits read to matching partitions
+	AccessQuery = "QUERY"
+	//AccessScan represents full table scan
+	AccessScan = "SCAN"
+)
+
+//Explain plan operations
+const (
+	explainPartiQL   = "PARTIQL"
+	explainAccess    = "ACCESS"
+	explainParameter = "PARAMETER"
+	explainClient    = "CLIENT"
+)
+
+//sortKeyOperators lists key condition operators applicable to sort key
+var sortKeyOperators = map[string]bool{"=": true, "<": true, ">": true, "<=": true, ">=": true, "=<": true, "=>": true, "BETWEEN": true, "BEGINS_WITH": true}
+
+//conjunction represents top level AND condition terms
+type conjunction []conditionTerm
+
+var explainColumns = []MetaColumn{{"Id", "N"}, {"Operation", "S"}, {"Target", "S"}, {"Detail", "S"}}
+
+//NewExplain creates an execution returning target execution plan rows without executing it
+func NewExplain(target *Execution, desc *types.TableDescription) (*Execution, error) {
+	if target.Parti == nil || target.Kind == KindExplain {
+		return nil, fmt.Errorf("%w: EXPLAIN %v", ErrUnsupported, target.SQL)
+	}
+	result := newMeta(KindExplain, target.Table, explainColumns)
+	for i, step := range target.explain(desc) {
+		item, err := newMetaItem(explainColumns, i+1, step[0], step[1], step[2])
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+//explain returns operation, target and detail plan steps
+func (e *Execution) explain(desc *types.TableDescription) [][3]string {
+	var result = [][3]string{{explainPartiQL, e.Table, e.Parti.Query}}
+	if e.Kind != KindInsert {
+		access, detail := accessPlan(e.criteria(), desc)
+		result = append(result, [3]string{explainAccess, e.Table, access + ": " + detail})
+	}
+	var params []*Parameter
+	params = append(params, e.Type.List...)
+	params = append(params, e.Type.Criteria...)
+	sort.SliceStable(params, func(i, j int) bool {
+		return params[i].Pos < params[j].Pos
+	})
+	for _, param := range params {
+		if param.Kind != ParameterKindPlaceholder {
+			continue
+		}
+		result = append(result, [3]string{explainParameter, param.Name, "$" + strconv.Itoa(param.Pos+1)})
+	}
+	if conflict := e.Conflict; conflict != nil {
+		detail := "DO NOTHING on DuplicateItemException"
+		if conflict.Update != nil {
+			detail = "on DuplicateItemException: " + conflict.Update.Parti.Query
+		}
+		result = append(result, [3]string{explainClient, "ON CONFLICT", detail})
+	}
+	if e.Kind != KindUndefined {
+		return result
+	}
+	aQuery := e.query
+	if aQuery.IsNested() {
+		result = append(result, [3]string{explainClient, "SELECT", "outer query projection of nested SELECT"})
+		aQuery = aQuery.NestedSelect()
+	}
+	for _, item := range aQuery.List {
+		call, ok := item.Expr.(*expr.Call)
+		if !ok {
+			continue
+		}
+		if _, ok := attributeTypeCast[strings.ToLower(stringify(call.X))]; ok {
+			continue
+		}
+		name := item.Alias
+		if name == "" {
+			name = stringify(call)
+		}
+		result = append(result, [3]string{explainClient, name, "function " + stringify(call)})
+	}
+	for _, column := range e.Type.Columns {
+		if column.DefaultValue != nil {
+			result = append(result, [3]string{explainClient, column.Name, fmt.Sprintf("COALESCE default: %v", column.DefaultValue)})
+		}
+	}
+	if e.Limit != nil {
+		result = append(result, [3]string{explainClient, "LIMIT", fmt.Sprintf("%v, sent as evaluated items limit per request, rows are truncated client side", *e.Limit)})
+	}
+	return result
+}
+
+//criteria returns WHERE clause expressions
+func (e *Execution) criteria() []node.Node {
+	var result []node.Node
+	if e.query == nil {
+		return nil
+	}
+	if e.query.IsNested() {
+		if nested := e.query.NestedSelect(); nested.Qualify != nil {
+			result = append(result, nested.Qualify.X)
+		}
+	}
+	if e.query.Qualify != nil {
+		result = append(result, e.query.Qualify.X)
+	}
+	return result
+}
+
+//accessPlan returns QUERY when top level conjunction has partition key equality (or IN) condition, SCAN otherwise
+func accessPlan(criteria []node.Node, desc *types.TableDescription) (string, string) {
+	var partitionKey, sortKey string
+	for _, key := range desc.KeySchema {
+		switch key.KeyType {
+		case types.KeyTypeHash:
+			partitionKey = *key.AttributeName
+		case types.KeyTypeRange:
+			sortKey = *key.AttributeName
+		}
+	}
+	var partition, ranges []string
+	for _, condition := range conjunctions(criteria) {
+		name, op := condition.attribute()
+		switch {
+		case name == "":
+		case name == partitionKey && (op == "=" || op == "IN"):
+			partition = append(partition, condition.String())
+		case name == sortKey && sortKey != "" && sortKeyOperators[op]:
+			ranges = append(ranges, condition.String())
+		}
+	}
+	if len(partition) == 0 {
+		return AccessScan, "full table scan, no top level equality condition on partition key " + partitionKey
+	}
+	detail := "partition key " + strings.Join(partition, " AND ")
+	if len(ranges) > 0 {
+		detail += ", sort key " + strings.Join(ranges, " AND ")
+	}
+	return AccessQuery, detail
+}
+
+//conjunctions splits criteria expression terms into top level AND conditions, criteria with top level OR yields no conditions
+func conjunctions(criteria []node.Node) []conjunction {
+	var result []conjunction
+	for _, n := range criteria {
+		var conditions []conjunction
+		var current conjunction
+		for _, term := range appendTerms(nil, n) {
+			if term.op == "OR" {
+				conditions, current = nil, nil
+				break
+			}
+			if term.op == "AND" {
+				conditions, current = append(conditions, current), nil
+				continue
+			}
+			current = append(current, term)
+		}
+		if current != nil {
+			conditions = append(conditions, current)
+		}
+		result = append(result, conditions...)
+	}
+	return result
+}
+
+//attribute returns condition attribute name and upper case operator, begins_with(name, prefix) returns BEGINS_WITH operator
+func (c conjunction) attribute() (string, string) {
+	switch len(c) {
+	case 1:
+		call, ok := c[0].node.(*expr.Call)
+		if !ok || !strings.EqualFold(stringify(call.X), "begins_with") || len(call.Args) == 0 {
+			return "", ""
+		}
+		return attributeName(call.Args[0]), "BEGINS_WITH"
+	case 3:
+		if c[0].node == nil || c[2].node == nil {
+			return "", ""
+		}
+		return attributeName(c[0].node), c[1].op
+	}
+	return "", ""
+}
+
+//String returns condition text
+func (c conjunction) String() string {
+	var parts []string
+	for _, term := range c {
+		if term.node != nil {
+			parts = append(parts, stringify(term.node))
+			continue
+		}
+		parts = append(parts, term.op)
+	}
+	return strings.Join(parts, " ")
+}
+
+//attributeName returns identifier or selector attribute name, or empty string
+func attributeName(n node.Node) string {
+	switch n.(type) {
+	case *expr.Ident, *expr.Selector:
+		return strings.Trim(stringify(n), `"`)
+	}
+	return ""
+}
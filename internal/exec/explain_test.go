@@ -0,0 +1,127 @@
+package exec_test
+
+import (
+	"database/sql/driver"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/francoispqt/gojay"
+	"github.com/stretchr/testify/assert"
+	ndynamodb "github.com/viant/dyndb/internal/dynamodb"
+	"github.com/viant/dyndb/internal/exec"
+	_ "github.com/viant/dyndb/internal/exec/fn"
+	"github.com/viant/sqlparser"
+	"testing"
+)
+
+func TestNewExplain(t *testing.T) {
+	table := "Publication"
+	desc := &types.TableDescription{
+		TableName: &table,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: stringPtr("ISBN"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: stringPtr("Published"), AttributeType: types.ScalarAttributeTypeN},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: stringPtr("ISBN"), KeyType: types.KeyTypeHash},
+			{AttributeName: stringPtr("Published"), KeyType: types.KeyTypeRange},
+		},
+	}
+	var testCases = []struct {
+		description string
+		SQL         string
+		update      bool
+		expect      [][]driver.Value
+	}{
+		{
+			description: "key condition query",
+			SQL:         "SELECT ISBN, Name FROM Publication WHERE ISBN = ? AND Published > ? AND Name = 'x'",
+			expect: [][]driver.Value{
+				{1, "PARTIQL", "Publication", "SELECT ISBN, Name FROM Publication WHERE ISBN = ? AND Published > ? AND Name = 'x'"},
+				{2, "ACCESS", "Publication", "QUERY: partition key ISBN = ?, sort key Published > ?"},
+				{3, "PARAMETER", "ISBN", "$1"},
+				{4, "PARAMETER", "Published", "$2"},
+			},
+		},
+		{
+			description: "key condition with nested disjunction",
+			SQL:         "SELECT ISBN FROM Publication WHERE (Name = ? OR Name = ?) AND ISBN IN (?, ?) AND Published > ?",
+			expect: [][]driver.Value{
+				{1, "PARTIQL", "Publication", "SELECT ISBN FROM Publication WHERE (Name = ? OR Name = ?) AND ISBN IN (?, ?) AND Published > ?"},
+				{2, "ACCESS", "Publication", "QUERY: partition key ISBN IN (?, ?), sort key Published > ?"},
+				{3, "PARAMETER", "Name", "$1"},
+				{4, "PARAMETER", "Name", "$2"},
+				{5, "PARAMETER", "", "$3"},
+				{6, "PARAMETER", "", "$4"},
+				{7, "PARAMETER", "Published", "$5"},
+			},
+		},
+		{
+			description: "full scan with client side function and limit",
+			SQL:         "SELECT ISBN, ARRAY_EXISTS(Categories, 'TRAVEL') AS IsTravel FROM Publication WHERE ISBN = ? OR Name = ? LIMIT 10",
+			expect: [][]driver.Value{
+				{1, "PARTIQL", "Publication", "SELECT ISBN, Categories FROM Publication WHERE ISBN = ? OR Name = ?"},
+				{2, "ACCESS", "Publication", "SCAN: full table scan, no top level equality condition on partition key ISBN"},
+				{3, "PARAMETER", "ISBN", "$1"},
+				{4, "PARAMETER", "Name", "$2"},
+				{5, "CLIENT", "IsTravel", "function ARRAY_EXISTS(Categories, 'TRAVEL')"},
+				{6, "CLIENT", "LIMIT", "10, sent as evaluated items limit per request, rows are truncated client side"},
+			},
+		},
+		{
+			description: "update",
+			SQL:         "UPDATE Publication SET Name = ? WHERE ISBN = ? AND Published = ?",
+			update:      true,
+			expect: [][]driver.Value{
+				{1, "PARTIQL", "Publication", "UPDATE Publication SET Name = ? WHERE ISBN = ? AND Published = ?"},
+				{2, "ACCESS", "Publication", "QUERY: partition key ISBN = ?, sort key Published = ?"},
+				{3, "PARAMETER", "Name", "$1"},
+				{4, "PARAMETER", "ISBN", "$2"},
+				{5, "PARAMETER", "Published", "$3"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		var target *exec.Execution
+		var err error
+		if testCase.update {
+			var stmt *exec.Update
+			if stmt, err = exec.ParseUpdate(testCase.SQL); assert.Nil(t, err, testCase.description) {
+				target, err = exec.NewUpdate(table, stmt, desc)
+			}
+		} else {
+			aQuery, parseErr := sqlparser.ParseQuery(testCase.SQL)
+			if err = parseErr; assert.Nil(t, err, testCase.description) {
+				target, err = exec.NewQuery(table, aQuery, desc)
+			}
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		execution, err := exec.NewExplain(target, desc)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		state := execution.NewQueryState(nil)
+		output := ndynamodb.NewExecuteStatementOutput(state.Type)
+		if err = output.Load(execution.Items...); !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if !assert.Nil(t, state.Init(), testCase.description) {
+			continue
+		}
+		var actual [][]driver.Value
+		for _, row := range output.Rows {
+			values := make([]driver.Value, len(state.Type.Columns))
+			state.SetDest(values)
+			err = gojay.Unmarshal(output.Data[row.Begin:row.End], state)
+			if err == nil {
+				err = state.Reconcile()
+			}
+			if !assert.Nil(t, err, testCase.description) {
+				break
+			}
+			actual = append(actual, values)
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
@@ -0,0 +1,525 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//condition represents client side evaluated WHERE clause expression
+type condition interface {
+	eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error)
+}
+
+type (
+	literalCond struct {
+		value interface{}
+	}
+	paramCond struct {
+		pos int
+	}
+	columnCond struct {
+		name string
+	}
+	notCond struct {
+		x condition
+	}
+	logicalCond struct {
+		op   string
+		x, y condition
+	}
+	compareCond struct {
+		op   string
+		x, y condition
+	}
+	inCond struct {
+		x    condition
+		list []condition
+		not  bool
+	}
+	//callCond represents PartiQL begins_with or contains function
+	callCond struct {
+		name      string
+		x, substr condition
+	}
+	nullCond struct {
+		x   condition
+		not bool
+	}
+	betweenCond struct {
+		x, min, max condition
+		not         bool
+	}
+)
+
+func (c *literalCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	return c.value, nil
+}
+
+func (c *paramCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	if c.pos >= len(args) {
+		return nil, fmt.Errorf("%w: missing argument for parameter %v", ErrInvalid, c.pos+1)
+	}
+	return args[c.pos].Value, nil
+}
+
+func (c *columnCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	value, ok := row[c.name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown column: %v", ErrInvalid, c.name)
+	}
+	return value, nil
+}
+
+func (c *notCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	value, err := c.x.eval(row, args)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return !isTrue(value), nil
+}
+
+func (c *logicalCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	x, err := c.x.eval(row, args)
+	if err != nil {
+		return nil, err
+	}
+	if c.op == "AND" && !isTrue(x) {
+		return false, nil
+	}
+	if c.op == "OR" && isTrue(x) {
+		return true, nil
+	}
+	y, err := c.y.eval(row, args)
+	if err != nil {
+		return nil, err
+	}
+	return isTrue(y), nil
+}
+
+func (c *compareCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	x, y, err := evalPair(c.x, c.y, row, args)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := compare(x, y)
+	if !ok {
+		return nil, nil
+	}
+	switch c.op {
+	case "=":
+		return result == 0, nil
+	case "<>", "!=":
+		return result != 0, nil
+	case "<":
+		return result < 0, nil
+	case "<=":
+		return result <= 0, nil
+	case ">":
+		return result > 0, nil
+	default:
+		return result >= 0, nil
+	}
+}
+
+func (c *inCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	x, err := c.x.eval(row, args)
+	if err != nil || x == nil {
+		return nil, err
+	}
+	for _, item := range c.list {
+		value, err := item.eval(row, args)
+		if err != nil {
+			return nil, err
+		}
+		if result, ok := compare(x, value); ok && result == 0 {
+			return !c.not, nil
+		}
+	}
+	return c.not, nil
+}
+
+func (c *callCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	x, substr, err := evalPair(c.x, c.substr, row, args)
+	if err != nil || x == nil || substr == nil {
+		return nil, err
+	}
+	if c.name == "begins_with" {
+		return strings.HasPrefix(toString(x), toString(substr)), nil
+	}
+	return strings.Contains(toString(x), toString(substr)), nil
+}
+
+func (c *nullCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	x, err := c.x.eval(row, args)
+	if err != nil {
+		return nil, err
+	}
+	return (x == nil) != c.not, nil
+}
+
+func (c *betweenCond) eval(row map[string]interface{}, args []driver.NamedValue) (interface{}, error) {
+	x, min, err := evalPair(c.x, c.min, row, args)
+	if err != nil {
+		return nil, err
+	}
+	max, err := c.max.eval(row, args)
+	if err != nil {
+		return nil, err
+	}
+	lower, ok := compare(x, min)
+	if !ok {
+		return nil, nil
+	}
+	upper, ok := compare(x, max)
+	if !ok {
+		return nil, nil
+	}
+	return (lower >= 0 && upper <= 0) != c.not, nil
+}
+
+func evalPair(x, y condition, row map[string]interface{}, args []driver.NamedValue) (interface{}, interface{}, error) {
+	xValue, err := x.eval(row, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	yValue, err := y.eval(row, args)
+	return xValue, yValue, err
+}
+
+func isTrue(value interface{}) bool {
+	result, ok := value.(bool)
+	return ok && result
+}
+
+//compare compares numbers, strings and booleans, it returns false if values are not comparable
+func compare(x, y interface{}) (int, bool) {
+	if x == nil || y == nil {
+		return 0, false
+	}
+	xNumber, xIsNumber := toNumber(x)
+	yNumber, yIsNumber := toNumber(y)
+	if xIsNumber || yIsNumber {
+		if !xIsNumber {
+			xNumber, xIsNumber = parseNumber(x)
+		}
+		if !yIsNumber {
+			yNumber, yIsNumber = parseNumber(y)
+		}
+		if !xIsNumber || !yIsNumber {
+			return 0, false
+		}
+		switch {
+		case xNumber < yNumber:
+			return -1, true
+		case xNumber > yNumber:
+			return 1, true
+		}
+		return 0, true
+	}
+	if xBool, ok := x.(bool); ok {
+		yBool, ok := y.(bool)
+		if !ok {
+			return 0, false
+		}
+		if xBool == yBool {
+			return 0, true
+		}
+		if !xBool {
+			return -1, true
+		}
+		return 1, true
+	}
+	return strings.Compare(toString(x), toString(y)), true
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	rValue := reflect.ValueOf(value)
+	switch rValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rValue.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rValue.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rValue.Float(), true
+	}
+	return 0, false
+}
+
+func parseNumber(value interface{}) (float64, bool) {
+	text, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	result, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	return result, err == nil
+}
+
+func toString(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+type (
+	//conditionBuilder builds condition from WHERE clause parsed by sqlparser
+	conditionBuilder struct {
+		params int
+		column func(name string) (string, error)
+	}
+
+	//conditionTerms represents operands and operators of parsed expression in source order,
+	//parser nodes do not follow operator precedence, thus it is applied on the terms
+	conditionTerms struct {
+		items []conditionTerm
+		index int
+	}
+
+	conditionTerm struct {
+		op   string
+		node node.Node
+	}
+)
+
+//parseCondition parses WHERE clause text with sqlparser, offset is the number of preceding placeholders, column returns canonical column name
+func parseCondition(text string, offset int, column func(name string) (string, error)) (condition, int, error) {
+	aQuery, err := sqlparser.ParseQuery("SELECT * FROM t WHERE " + text)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: WHERE %v: %v", ErrUnsupported, text, err)
+	}
+	if aQuery.Qualify == nil {
+		return nil, 0, fmt.Errorf("%w: WHERE %v", ErrUnsupported, text)
+	}
+	builder := &conditionBuilder{params: offset, column: column}
+	result, err := builder.build(aQuery.Qualify.X)
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, builder.params - offset, nil
+}
+
+func (b *conditionBuilder) build(n node.Node) (condition, error) {
+	terms := &conditionTerms{items: appendTerms(nil, n)}
+	result, err := b.or(terms)
+	if err == nil && terms.index < len(terms.items) {
+		err = fmt.Errorf("%w: unexpected %v in WHERE clause", ErrUnsupported, terms.peek())
+	}
+	return result, err
+}
+
+//appendTerms appends expression terms in source order
+func appendTerms(terms []conditionTerm, n node.Node) []conditionTerm {
+	switch actual := n.(type) {
+	case *expr.Binary:
+		terms = appendTerms(terms, actual.X)
+		if actual.Op == "" {
+			return terms
+		}
+		terms = append(terms, conditionTerm{op: strings.ToUpper(actual.Op)})
+		return appendTerms(terms, actual.Y)
+	case *expr.Unary:
+		terms = append(terms, conditionTerm{op: strings.ToUpper(actual.Op)})
+		return appendTerms(terms, actual.X)
+	}
+	return append(terms, conditionTerm{node: n})
+}
+
+func (t *conditionTerms) peek() string {
+	if t.index < len(t.items) {
+		if t.items[t.index].node != nil {
+			return sqlparser.Stringify(t.items[t.index].node)
+		}
+		return t.items[t.index].op
+	}
+	return ""
+}
+
+//accept consumes next term if it is the operator
+func (t *conditionTerms) accept(op string) bool {
+	if t.index < len(t.items) && t.items[t.index].node == nil && t.items[t.index].op == op {
+		t.index++
+		return true
+	}
+	return false
+}
+
+func (t *conditionTerms) next() (node.Node, error) {
+	if t.index >= len(t.items) {
+		return nil, fmt.Errorf("%w: unexpected end of WHERE clause", ErrUnsupported)
+	}
+	term := t.items[t.index]
+	if term.node == nil {
+		return nil, fmt.Errorf("%w: unexpected %v in WHERE clause", ErrUnsupported, term.op)
+	}
+	t.index++
+	return term.node, nil
+}
+
+func (b *conditionBuilder) or(terms *conditionTerms) (condition, error) {
+	x, err := b.and(terms)
+	for err == nil && terms.accept("OR") {
+		var y condition
+		if y, err = b.and(terms); err == nil {
+			x = &logicalCond{op: "OR", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (b *conditionBuilder) and(terms *conditionTerms) (condition, error) {
+	x, err := b.not(terms)
+	for err == nil && terms.accept("AND") {
+		var y condition
+		if y, err = b.not(terms); err == nil {
+			x = &logicalCond{op: "AND", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (b *conditionBuilder) not(terms *conditionTerms) (condition, error) {
+	if terms.accept("NOT") {
+		x, err := b.not(terms)
+		if err != nil {
+			return nil, err
+		}
+		return &notCond{x: x}, nil
+	}
+	return b.predicate(terms)
+}
+
+func (b *conditionBuilder) predicate(terms *conditionTerms) (condition, error) {
+	x, err := b.nextOperand(terms)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"=", "!=", "<", ">"} {
+		if terms.accept(op) {
+			y, err := b.nextOperand(terms)
+			if err != nil {
+				return nil, err
+			}
+			return &compareCond{op: op, x: x, y: y}, nil
+		}
+	}
+	switch {
+	case terms.accept("IS"), terms.accept("IS NOT"):
+		not := terms.items[terms.index-1].op == "IS NOT"
+		y, err := b.nextOperand(terms)
+		if err != nil {
+			return nil, err
+		}
+		if literal, ok := y.(*literalCond); !ok || literal.value != nil {
+			return nil, fmt.Errorf("%w: expected NULL after IS", ErrUnsupported)
+		}
+		return &nullCond{x: x, not: not}, nil
+	case terms.accept("IN"), terms.accept("NOT IN"):
+		result := &inCond{x: x, not: terms.items[terms.index-1].op == "NOT IN"}
+		n, err := terms.next()
+		if err != nil {
+			return nil, err
+		}
+		group, ok := n.(*expr.Parenthesis)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected IN list, but had: %v", ErrUnsupported, sqlparser.Stringify(n))
+		}
+		list, err := sqlparser.ParseList(strings.Trim(group.Raw, "()"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: IN %v: %v", ErrUnsupported, group.Raw, err)
+		}
+		for _, item := range list {
+			value, err := b.operand(item.Expr)
+			if err != nil {
+				return nil, err
+			}
+			result.list = append(result.list, value)
+		}
+		return result, nil
+	case terms.accept("BETWEEN"):
+		n, err := terms.next()
+		if err != nil {
+			return nil, err
+		}
+		bounds, ok := n.(*expr.Range)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected BETWEEN range, but had: %v", ErrUnsupported, sqlparser.Stringify(n))
+		}
+		result := &betweenCond{x: x}
+		if result.min, err = b.operand(bounds.Min); err != nil {
+			return nil, err
+		}
+		result.max, err = b.operand(bounds.Max)
+		return result, err
+	}
+	return x, nil
+}
+
+func (b *conditionBuilder) nextOperand(terms *conditionTerms) (condition, error) {
+	n, err := terms.next()
+	if err != nil {
+		return nil, err
+	}
+	return b.operand(n)
+}
+
+func (b *conditionBuilder) operand(n node.Node) (condition, error) {
+	switch actual := n.(type) {
+	case *expr.Parenthesis:
+		if actual.X == nil {
+			return nil, fmt.Errorf("%w: unexpected %v in WHERE clause", ErrUnsupported, actual.Raw)
+		}
+		return b.build(actual.X)
+	case *expr.Placeholder:
+		b.params++
+		return &paramCond{pos: b.params - 1}, nil
+	case *expr.Literal:
+		return literal(actual)
+	case *expr.Ident, *expr.Selector:
+		name, err := b.column(sqlparser.Stringify(actual))
+		if err != nil {
+			return nil, err
+		}
+		return &columnCond{name: name}, nil
+	case *expr.Call:
+		name := strings.ToLower(sqlparser.Stringify(actual.X))
+		if (name != "begins_with" && name != "contains") || len(actual.Args) != 2 {
+			return nil, fmt.Errorf("%w: function %v, supported: begins_with(column, value), contains(column, value)", ErrUnsupported, actual.Raw)
+		}
+		result := &callCond{name: name}
+		var err error
+		if result.x, err = b.operand(actual.Args[0]); err != nil {
+			return nil, err
+		}
+		result.substr, err = b.operand(actual.Args[1])
+		return result, err
+	case *expr.Binary, *expr.Unary:
+		return b.build(actual)
+	}
+	return nil, fmt.Errorf("%w: unexpected %v in WHERE clause", ErrUnsupported, sqlparser.Stringify(n))
+}
+
+func literal(n *expr.Literal) (condition, error) {
+	switch n.Kind {
+	case "null":
+		return &literalCond{}, nil
+	case "bool":
+		return &literalCond{value: strings.EqualFold(n.Value, "true")}, nil
+	case "int", "numeric":
+		value, err := strconv.ParseFloat(n.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid number: %v", ErrUnsupported, n.Value)
+		}
+		return &literalCond{value: value}, nil
+	}
+	value := n.Value
+	if len(value) >= 2 {
+		value = value[1 : len(value)-1]
+	}
+	return &literalCond{value: strings.ReplaceAll(value, `\'`, "'")}, nil
+}
@@ -0,0 +1,57 @@
+package exec
+
+import (
+	"strings"
+)
+
+//Hints represents optimizer style /*+ ... */ statement hints
+type Hints struct {
+	//ConsistentRead is set by /*+ consistent */ or /*+ eventual */ hint
+	ConsistentRead *bool
+}
+
+//ParseHints strips /*+ hint[, ...] */ comments from SQL and returns recognized hints
+func ParseHints(SQL string) (string, *Hints) {
+	hints := &Hints{}
+	for {
+		begin := hintIndex(SQL)
+		if begin == -1 {
+			return SQL, hints
+		}
+		end := strings.Index(SQL[begin:], "*/")
+		if end == -1 {
+			return SQL, hints
+		}
+		end += begin
+		for _, hint := range strings.FieldsFunc(SQL[begin+3:end], func(r rune) bool { return r == ',' || r == ' ' || r == '\n' || r == '\t' }) {
+			switch strings.ToLower(hint) {
+			case "consistent", "consistent_read":
+				value := true
+				hints.ConsistentRead = &value
+			case "eventual", "eventually_consistent":
+				value := false
+				hints.ConsistentRead = &value
+			}
+		}
+		SQL = SQL[:begin] + " " + SQL[end+2:]
+	}
+}
+
+//hintIndex returns position of the first /*+ outside of quoted literals or -1
+func hintIndex(SQL string) int {
+	quote := byte(0)
+	for i := 0; i < len(SQL); i++ {
+		c := SQL[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case strings.HasPrefix(SQL[i:], "/*+"):
+			return i
+		}
+	}
+	return -1
+}
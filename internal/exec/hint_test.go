@@ -0,0 +1,31 @@
+package exec_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dyndb/internal/exec"
+	"testing"
+)
+
+func TestParseHints(t *testing.T) {
+	var testCases = []struct {
+		description string
+		SQL         string
+		expectSQL   string
+		expect      *bool
+	}{
+		{description: "no hint", SQL: "SELECT * FROM t", expectSQL: "SELECT * FROM t"},
+		{description: "consistent", SQL: "SELECT /*+ consistent */ * FROM t", expectSQL: "SELECT   * FROM t", expect: boolPtr(true)},
+		{description: "eventual", SQL: "SELECT /*+ EVENTUAL */ * FROM t", expectSQL: "SELECT   * FROM t", expect: boolPtr(false)},
+		{description: "quoted", SQL: "SELECT * FROM t WHERE c = '/*+ consistent */'", expectSQL: "SELECT * FROM t WHERE c = '/*+ consistent */'"},
+		{description: "quoted and hint", SQL: "SELECT /*+ consistent */ * FROM t WHERE c = 'a /*+ eventual */'", expectSQL: "SELECT   * FROM t WHERE c = 'a /*+ eventual */'", expect: boolPtr(true)},
+	}
+	for _, testCase := range testCases {
+		SQL, hints := exec.ParseHints(testCase.SQL)
+		assert.EqualValues(t, testCase.expectSQL, SQL, testCase.description)
+		assert.EqualValues(t, testCase.expect, hints.ConsistentRead, testCase.description)
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
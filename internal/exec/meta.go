@@ -0,0 +1,202 @@
+package exec
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strings"
+)
+
+//MetaColumn represents client side synthesized result column
+type MetaColumn struct {
+	Name string
+	Type string
+}
+
+var (
+	tableColumns    = []MetaColumn{{"Table", "S"}}
+	describeColumns = []MetaColumn{{"Table", "S"}, {"Attribute", "S"}, {"Type", "S"}, {"KeyRole", "S"}, {"Indexes", "S"},
+		{"ItemCount", "N"}, {"SizeBytes", "N"}, {"Status", "S"}, {"BillingMode", "S"}}
+	indexColumns = []MetaColumn{{"Table", "S"}, {"Index", "S"}, {"Kind", "S"}, {"Attribute", "S"}, {"KeyRole", "S"},
+		{"Projection", "S"}, {"NonKeyAttributes", "S"}, {"Status", "S"}, {"ItemCount", "N"}}
+)
+
+const (
+	indexPrimary = "PRIMARY"
+	indexGlobal  = "GLOBAL"
+	indexLocal   = "LOCAL"
+)
+
+//NewMetaType creates row type for client side synthesized rows
+func NewMetaType(columns ...MetaColumn) *Type {
+	result := NewType(false)
+	for _, column := range columns {
+		result.Add(column.Name, "", column.Type, false)
+	}
+	return result
+}
+
+//ParseMeta parses SHOW TABLES, DESCRIBE [TABLE] table and SHOW INDEX|INDEXES|KEYS FROM|IN table statements
+func ParseMeta(SQL string) (Kind, string, error) {
+	fields := strings.Fields(strings.TrimRight(strings.TrimSpace(SQL), ";"))
+	keywords := strings.Fields(strings.ToUpper(strings.Join(fields, " ")))
+	switch {
+	case len(fields) == 2 && keywords[0] == "SHOW" && keywords[1] == "TABLES":
+		return KindShowTables, "", nil
+	case len(fields) == 2 && (keywords[0] == "DESCRIBE" || keywords[0] == "DESC"):
+		return KindDescribe, tableName(fields[1]), nil
+	case len(fields) == 3 && (keywords[0] == "DESCRIBE" || keywords[0] == "DESC") && keywords[1] == "TABLE":
+		return KindDescribe, tableName(fields[2]), nil
+	case len(fields) == 4 && keywords[0] == "SHOW" && (keywords[1] == "INDEX" || keywords[1] == "INDEXES" || keywords[1] == "KEYS") && (keywords[2] == "FROM" || keywords[2] == "IN"):
+		return KindShowIndexes, tableName(fields[3]), nil
+	}
+	return KindUndefined, "", fmt.Errorf("%w: %v", ErrUnsupported, SQL)
+}
+
+func tableName(name string) string {
+	return strings.Trim(name, "\"`'")
+}
+
+//NewMeta creates SHOW TABLES, DESCRIBE or SHOW INDEXES execution, rows are loaded when executed
+func NewMeta(kind Kind, table string) (*Execution, error) {
+	switch kind {
+	case KindShowTables:
+		return newMeta(kind, table, tableColumns), nil
+	case KindDescribe:
+		return newMeta(kind, table, describeColumns), nil
+	case KindShowIndexes:
+		return newMeta(kind, table, indexColumns), nil
+	}
+	return nil, fmt.Errorf("%w: metadata statement kind: %v", ErrUnsupported, kind)
+}
+
+func newMeta(kind Kind, table string, columns []MetaColumn) *Execution {
+	result := &Execution{Kind: kind, Table: table, Type: NewMetaType(columns...)}
+	result.initState()
+	return result
+}
+
+//IsMeta returns true if execution returns client side synthesized rows
+func (e *Execution) IsMeta() bool {
+	switch e.Kind {
+	case KindExplain, KindShowTables, KindDescribe, KindShowIndexes, KindSchema, KindStream:
+		return true
+	}
+	return false
+}
+
+//TableItems returns SHOW TABLES rows
+func TableItems(tables []string) ([]map[string]types.AttributeValue, error) {
+	var result []map[string]types.AttributeValue
+	for _, table := range tables {
+		item, err := newMetaItem(tableColumns, table)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+//DescribeItems returns DESCRIBE rows, one per defined attribute
+func DescribeItems(desc *types.TableDescription) ([]map[string]types.AttributeValue, error) {
+	indexes := map[string][]string{}
+	for _, index := range tableIndexes(desc) {
+		for _, key := range index.keys {
+			indexes[*key.AttributeName] = append(indexes[*key.AttributeName], index.name+":"+string(key.KeyType))
+		}
+	}
+	var result []map[string]types.AttributeValue
+	for _, attr := range desc.AttributeDefinitions {
+		name := *attr.AttributeName
+		keyRole := ""
+		for _, key := range desc.KeySchema {
+			if *key.AttributeName == name {
+				keyRole = string(key.KeyType)
+			}
+		}
+		item, err := newMetaItem(describeColumns, stringValue(desc.TableName), name, string(attr.AttributeType), keyRole,
+			strings.Join(indexes[name], ", "), desc.ItemCount, desc.TableSizeBytes, string(desc.TableStatus), billingMode(desc))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+//IndexItems returns SHOW INDEXES rows, one per index key attribute, table primary key goes first
+func IndexItems(desc *types.TableDescription) ([]map[string]types.AttributeValue, error) {
+	var result []map[string]types.AttributeValue
+	for _, index := range tableIndexes(desc) {
+		for _, key := range index.keys {
+			item, err := newMetaItem(indexColumns, stringValue(desc.TableName), index.name, index.kind, *key.AttributeName,
+				string(key.KeyType), index.projection, strings.Join(index.nonKeyAttributes, ", "), index.status, index.itemCount)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+type tableIndex struct {
+	name             string
+	kind             string
+	keys             []types.KeySchemaElement
+	projection       string
+	nonKeyAttributes []string
+	status           string
+	itemCount        *int64
+}
+
+func tableIndexes(desc *types.TableDescription) []*tableIndex {
+	var result = []*tableIndex{{name: indexPrimary, kind: indexPrimary, keys: desc.KeySchema, projection: string(types.ProjectionTypeAll),
+		status: string(desc.TableStatus), itemCount: desc.ItemCount}}
+	for _, index := range desc.GlobalSecondaryIndexes {
+		item := &tableIndex{name: stringValue(index.IndexName), kind: indexGlobal, keys: index.KeySchema, status: string(index.IndexStatus), itemCount: index.ItemCount}
+		item.setProjection(index.Projection)
+		result = append(result, item)
+	}
+	for _, index := range desc.LocalSecondaryIndexes {
+		item := &tableIndex{name: stringValue(index.IndexName), kind: indexLocal, keys: index.KeySchema, status: string(desc.TableStatus), itemCount: index.ItemCount}
+		item.setProjection(index.Projection)
+		result = append(result, item)
+	}
+	return result
+}
+
+func (i *tableIndex) setProjection(projection *types.Projection) {
+	if projection == nil {
+		return
+	}
+	i.projection = string(projection.ProjectionType)
+	i.nonKeyAttributes = projection.NonKeyAttributes
+}
+
+func billingMode(desc *types.TableDescription) string {
+	if desc.BillingModeSummary != nil {
+		return string(desc.BillingModeSummary.BillingMode)
+	}
+	return string(types.BillingModeProvisioned)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+//newMetaItem returns synthesized row item, values are matched with columns by position
+func newMetaItem(columns []MetaColumn, values ...interface{}) (map[string]types.AttributeValue, error) {
+	var result = make(map[string]types.AttributeValue, len(columns))
+	for i, value := range values {
+		attrValue, err := Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %v: %w", columns[i].Name, err)
+		}
+		result[columns[i].Name] = attrValue
+	}
+	return result, nil
+}
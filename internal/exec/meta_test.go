@@ -0,0 +1,85 @@
+package exec_test
+
+import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dyndb/internal/exec"
+	"testing"
+)
+
+func TestParseMeta(t *testing.T) {
+	var testCases = []struct {
+		description string
+		SQL         string
+		expectKind  exec.Kind
+		expectTable string
+		hasError    bool
+	}{
+		{description: "show tables", SQL: "show tables;", expectKind: exec.KindShowTables},
+		{description: "describe", SQL: "DESCRIBE Publication", expectKind: exec.KindDescribe, expectTable: "Publication"},
+		{description: "describe table", SQL: "DESC TABLE `Publication`", expectKind: exec.KindDescribe, expectTable: "Publication"},
+		{description: "show indexes", SQL: "SHOW INDEXES FROM Publication", expectKind: exec.KindShowIndexes, expectTable: "Publication"},
+		{description: "show keys", SQL: "show keys in Publication", expectKind: exec.KindShowIndexes, expectTable: "Publication"},
+		{description: "unsupported", SQL: "SHOW DATABASES", hasError: true},
+	}
+	for _, testCase := range testCases {
+		kind, table, err := exec.ParseMeta(testCase.SQL)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectKind, kind, testCase.description)
+		assert.EqualValues(t, testCase.expectTable, table, testCase.description)
+	}
+}
+
+func TestDescribeItems(t *testing.T) {
+	itemCount := int64(3)
+	desc := &types.TableDescription{
+		TableName:   stringPtr("Publication"),
+		TableStatus: types.TableStatusActive,
+		ItemCount:   &itemCount,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: stringPtr("ISBN"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: stringPtr("Name"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: stringPtr("ISBN"), KeyType: types.KeyTypeHash},
+		},
+		BillingModeSummary: &types.BillingModeSummary{BillingMode: types.BillingModePayPerRequest},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+			{
+				IndexName:   stringPtr("ByName"),
+				IndexStatus: types.IndexStatusActive,
+				KeySchema:   []types.KeySchemaElement{{AttributeName: stringPtr("Name"), KeyType: types.KeyTypeHash}},
+				Projection:  &types.Projection{ProjectionType: types.ProjectionTypeInclude, NonKeyAttributes: []string{"Price", "Tags"}},
+			},
+		},
+	}
+
+	items, err := exec.DescribeItems(desc)
+	assert.Nil(t, err)
+	var actual []map[string]interface{}
+	assert.Nil(t, attributevalue.UnmarshalListOfMaps(items, &actual))
+	assert.EqualValues(t, []map[string]interface{}{
+		{"Table": "Publication", "Attribute": "ISBN", "Type": "S", "KeyRole": "HASH", "Indexes": "PRIMARY:HASH",
+			"ItemCount": 3.0, "SizeBytes": nil, "Status": "ACTIVE", "BillingMode": "PAY_PER_REQUEST"},
+		{"Table": "Publication", "Attribute": "Name", "Type": "S", "KeyRole": "", "Indexes": "ByName:HASH",
+			"ItemCount": 3.0, "SizeBytes": nil, "Status": "ACTIVE", "BillingMode": "PAY_PER_REQUEST"},
+	}, actual)
+
+	items, err = exec.IndexItems(desc)
+	assert.Nil(t, err)
+	actual = nil
+	assert.Nil(t, attributevalue.UnmarshalListOfMaps(items, &actual))
+	assert.EqualValues(t, []map[string]interface{}{
+		{"Table": "Publication", "Index": "PRIMARY", "Kind": "PRIMARY", "Attribute": "ISBN", "KeyRole": "HASH",
+			"Projection": "ALL", "NonKeyAttributes": "", "Status": "ACTIVE", "ItemCount": 3.0},
+		{"Table": "Publication", "Index": "ByName", "Kind": "GLOBAL", "Attribute": "Name", "KeyRole": "HASH",
+			"Projection": "INCLUDE", "NonKeyAttributes": "Price, Tags", "Status": "ACTIVE", "ItemCount": nil},
+	}, actual)
+}
@@ -0,0 +1,128 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+//ParseNamed replaces :name and @name placeholders outside of literals, quoted identifiers and comments with ?, it returns names in placeholder order
+func ParseNamed(SQL string) (string, []string, error) {
+	var result strings.Builder
+	var names []string
+	positional := false
+	for i := 0; i < len(SQL); i++ {
+		c := SQL[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(SQL[i+1:], c)
+			if end == -1 {
+				result.WriteString(SQL[i:])
+				i = len(SQL)
+				continue
+			}
+			end += i + 1
+			result.WriteString(SQL[i : end+1])
+			i = end
+			continue
+		case c == '-' && strings.HasPrefix(SQL[i:], "--"), c == '/' && strings.HasPrefix(SQL[i:], "/*"):
+			terminator := "\n"
+			if c == '/' {
+				terminator = "*/"
+			}
+			end := strings.Index(SQL[i+2:], terminator)
+			if end == -1 {
+				result.WriteString(SQL[i:])
+				i = len(SQL)
+				continue
+			}
+			end += i + 2 + len(terminator)
+			result.WriteString(SQL[i:end])
+			i = end - 1
+			continue
+		case c == '?':
+			positional = true
+		case (c == ':' || c == '@') && i+1 < len(SQL) && isIdentStart(SQL[i+1]) && (i == 0 || !isIdentPart(SQL[i-1]) && SQL[i-1] != ':'):
+			end := i + 1
+			for end < len(SQL) && isIdentPart(SQL[end]) {
+				end++
+			}
+			names = append(names, SQL[i+1:end])
+			result.WriteByte('?')
+			i = end - 1
+			continue
+		}
+		result.WriteByte(c)
+	}
+	if len(names) == 0 {
+		return SQL, nil, nil
+	}
+	if positional {
+		return "", nil, fmt.Errorf("%w: mixed positional (?) and named parameters: %v", ErrInvalid, SQL)
+	}
+	return result.String(), names, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+//NumNamed returns number of distinct parameter names
+func (e *Execution) NumNamed() int {
+	index := map[string]bool{}
+	for _, name := range e.Names {
+		index[name] = true
+	}
+	return len(index)
+}
+
+//BindNamed returns positional arguments for named placeholders, named arguments are rejected by statement with positional placeholders
+func (e *Execution) BindNamed(args []driver.NamedValue) ([]driver.NamedValue, error) {
+	if len(e.Names) == 0 {
+		for _, arg := range args {
+			if arg.Name != "" {
+				return nil, fmt.Errorf("%w: named argument %v, but statement uses positional parameters", ErrInvalid, arg.Name)
+			}
+		}
+		return args, nil
+	}
+	index := make(map[string]int, len(args))
+	for i, arg := range args {
+		if arg.Name == "" {
+			return nil, fmt.Errorf("%w: positional argument %v, but statement uses named parameters, use sql.Named", ErrInvalid, arg.Ordinal)
+		}
+		index[arg.Name] = i
+	}
+	result := make([]driver.NamedValue, len(e.Names))
+	used := make(map[string]bool, len(args))
+	var missing []string
+	for i, name := range e.Names {
+		pos, ok := index[name]
+		if !ok {
+			if !used[name] {
+				missing = append(missing, ":"+name)
+			}
+			used[name] = true
+			continue
+		}
+		used[name] = true
+		result[i] = driver.NamedValue{Ordinal: i + 1, Value: args[pos].Value}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing named argument(s): %v", ErrInvalid, strings.Join(missing, ", "))
+	}
+	var unused []string
+	for _, arg := range args {
+		if !used[arg.Name] {
+			unused = append(unused, arg.Name)
+		}
+	}
+	if len(unused) > 0 {
+		return nil, fmt.Errorf("%w: unused named argument(s): %v", ErrInvalid, strings.Join(unused, ", "))
+	}
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package exec_test
+
+import (
+	"database/sql/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dyndb/internal/exec"
+	"testing"
+)
+
+func TestParseNamed(t *testing.T) {
+	var testCases = []struct {
+		description string
+		SQL         string
+		expect      string
+		names       []string
+		hasError    bool
+	}{
+		{
+			description: "colon and at names",
+			SQL:         "SELECT * FROM Publication WHERE ISBN = :isbn AND Name = @name OR Author = :isbn",
+			expect:      "SELECT * FROM Publication WHERE ISBN = ? AND Name = ? OR Author = ?",
+			names:       []string{"isbn", "name", "isbn"},
+		},
+		{
+			description: "literals, quoted identifiers and comments",
+			SQL:         "UPDATE Users /*+ :hint */ SET Email = 'bob@example.com:x', \"a:b\" = :value -- :comment\nWHERE Id=:id",
+			expect:      "UPDATE Users /*+ :hint */ SET Email = 'bob@example.com:x', \"a:b\" = ? -- :comment\nWHERE Id=?",
+			names:       []string{"value", "id"},
+		},
+		{
+			description: "positional",
+			SQL:         "SELECT * FROM Users WHERE Id = ?",
+			expect:      "SELECT * FROM Users WHERE Id = ?",
+		},
+		{
+			description: "mixed",
+			SQL:         "SELECT * FROM Users WHERE Id = ? AND Name = :name",
+			hasError:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, names, err := exec.ParseNamed(testCase.SQL)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+		assert.EqualValues(t, testCase.names, names, testCase.description)
+	}
+}
+
+func TestExecution_BindNamed(t *testing.T) {
+	var testCases = []struct {
+		description string
+		names       []string
+		args        []driver.NamedValue
+		expect      []driver.NamedValue
+		hasError    bool
+	}{
+		{
+			description: "repeated name",
+			names:       []string{"id", "name", "id"},
+			args:        []driver.NamedValue{{Name: "name", Ordinal: 1, Value: "Bob"}, {Name: "id", Ordinal: 2, Value: 1}},
+			expect:      []driver.NamedValue{{Ordinal: 1, Value: 1}, {Ordinal: 2, Value: "Bob"}, {Ordinal: 3, Value: 1}},
+		},
+		{
+			description: "missing name",
+			names:       []string{"id", "name"},
+			args:        []driver.NamedValue{{Name: "id", Ordinal: 1, Value: 1}},
+			hasError:    true,
+		},
+		{
+			description: "unused name",
+			names:       []string{"id"},
+			args:        []driver.NamedValue{{Name: "id", Ordinal: 1, Value: 1}, {Name: "name", Ordinal: 2, Value: "Bob"}},
+			hasError:    true,
+		},
+		{
+			description: "positional argument with named parameters",
+			names:       []string{"id"},
+			args:        []driver.NamedValue{{Ordinal: 1, Value: 1}},
+			hasError:    true,
+		},
+		{
+			description: "named argument with positional parameters",
+			args:        []driver.NamedValue{{Name: "id", Ordinal: 1, Value: 1}},
+			hasError:    true,
+		},
+		{
+			description: "positional",
+			args:        []driver.NamedValue{{Ordinal: 1, Value: 1}},
+			expect:      []driver.NamedValue{{Ordinal: 1, Value: 1}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		execution := &exec.Execution{Names: testCase.names}
+		actual, err := execution.BindNamed(testCase.args)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
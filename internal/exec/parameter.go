@@ -7,15 +7,15 @@ import (
 
 const (
 	//ParameterKindLiteral literal
-	ParameterKindLiteral = ParameterKind(1)
+	ParameterKindLiteral = ParameterKind("literal")
 	//ParameterKindPlaceholder placeholder
-	ParameterKindPlaceholder = ParameterKind(2)
+	ParameterKindPlaceholder = ParameterKind("placeholder")
 	//ParameterKindField field
-	ParameterKindField = ParameterKind(3)
+	ParameterKindField = ParameterKind("field")
 	//ParameterKindColumn column
-	ParameterKindColumn = ParameterKind(4)
+	ParameterKindColumn = ParameterKind("column")
 	//ParameterKindValue values
-	ParameterKindValue = ParameterKind(5)
+	ParameterKindValue = ParameterKind("value")
 )
 
 type (
@@ -23,29 +23,25 @@ type (
 	ParameterKind string
 	//Parameter represents query parameters
 	Parameter struct {
-		Name  string
-		Type  reflect.Type
-		Kind  ParameterKind
-		Pos   int
-		Value interface{} //for constants
+		Name   string
+		Type   reflect.Type
+		Kind   ParameterKind
+		Pos    int
+		Value  interface{} //for constants
+		InList bool        //sole IN list placeholder, slice argument is expanded
 	}
 	//Parameters parameter collections
 	Parameters struct {
 		BindingLen int
 		List       []*Parameter
 		Criteria   []*Parameter
-		numInput   int
 	}
 )
 
 //AddCriteria add criteria paramaeter
 func (p *Parameters) AddCriteria(param *Parameter) {
 	p.initParam(param)
-	if param.Kind == ParameterKindPlaceholder {
-		p.numInput++
-	}
 	p.Criteria = append(p.Criteria, param)
-
 }
 
 //AddItem add param
@@ -63,7 +59,7 @@ func (p *Parameters) initParam(param *Parameter) {
 
 //NumInput returns num inputs
 func (p *Parameters) NumInput() int {
-	return p.numInput
+	return p.BindingLen
 }
 
 //NewLiteral returns a literal param
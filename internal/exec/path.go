@@ -0,0 +1,217 @@
+package exec
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	//Path represents a document path node, i.e. Info.Address.City or Tags[0]
+	Path struct {
+		Name    string
+		Pos     int
+		Keys    map[string]*Path
+		Items   []*Path
+		indexes []int
+	}
+
+	//PathElement represents document path element
+	PathElement struct {
+		Name  string
+		Index int
+	}
+)
+
+//IsNested returns true if path has map keys or list indexes
+func (p *Path) IsNested() bool {
+	return len(p.Keys) > 0 || len(p.Items) > 0
+}
+
+//IsProjected returns true if path is projected as a whole
+func (p *Path) IsProjected() bool {
+	return p.Pos != -1
+}
+
+//Key returns map key path or nil
+func (p *Path) Key(name string) *Path {
+	return p.Keys[name]
+}
+
+//Item returns list item path for supplied response position or nil,
+//DynamoDB returns only projected list elements in ascending index order
+func (p *Path) Item(position int) *Path {
+	if position < len(p.Items) {
+		return p.Items[position]
+	}
+	return nil
+}
+
+//Index returns list item path for supplied list index or nil
+func (p *Path) Index(index int) *Path {
+	for i, candidate := range p.indexes {
+		if candidate == index {
+			return p.Items[i]
+		}
+	}
+	return nil
+}
+
+func (p *Path) clone() *Path {
+	result := &Path{Name: p.Name, Pos: p.Pos, indexes: append([]int{}, p.indexes...)}
+	if p.Keys != nil {
+		result.Keys = make(map[string]*Path, len(p.Keys))
+		for k, v := range p.Keys {
+			result.Keys[k] = v.clone()
+		}
+	}
+	for _, item := range p.Items {
+		result.Items = append(result.Items, item.clone())
+	}
+	return result
+}
+
+func (p *Path) add(elements []PathElement, pos int) {
+	node := p
+	for _, element := range elements {
+		node = node.child(element)
+	}
+	node.Pos = pos
+}
+
+func (p *Path) child(element PathElement) *Path {
+	if element.Index == -1 {
+		if p.Keys == nil {
+			p.Keys = map[string]*Path{}
+		}
+		result, ok := p.Keys[element.Name]
+		if !ok {
+			result = newPath(element.Name)
+			p.Keys[element.Name] = result
+		}
+		return result
+	}
+	if result := p.Index(element.Index); result != nil {
+		return result
+	}
+	result := newPath(strconv.Itoa(element.Index))
+	at := sort.SearchInts(p.indexes, element.Index)
+	p.indexes = append(p.indexes, 0)
+	copy(p.indexes[at+1:], p.indexes[at:])
+	p.indexes[at] = element.Index
+	p.Items = append(p.Items, nil)
+	copy(p.Items[at+1:], p.Items[at:])
+	p.Items[at] = result
+	return result
+}
+
+func newPath(name string) *Path {
+	return &Path{Name: name, Pos: -1}
+}
+
+//ParsePath parses document path into elements
+func ParsePath(name string) []PathElement {
+	var result []PathElement
+	name = unescapeIndexes(name)
+	for _, part := range strings.Split(name, ".") {
+		index := strings.IndexByte(part, '[')
+		if index == -1 {
+			result = append(result, PathElement{Name: part, Index: -1})
+			continue
+		}
+		if index > 0 {
+			result = append(result, PathElement{Name: part[:index], Index: -1})
+		}
+		for _, item := range strings.Split(part[index+1:], "[") {
+			value, err := strconv.Atoi(strings.TrimRight(item, "]"))
+			if err != nil {
+				return append(result, PathElement{Name: part, Index: -1})
+			}
+			result = append(result, PathElement{Index: value})
+		}
+	}
+	return result
+}
+
+//EscapeIndexes rewrites document path list indexes (Tags[0]) into Tags:0 form accepted by SQL parser
+func EscapeIndexes(SQL string) string {
+	if strings.IndexByte(SQL, '[') == -1 {
+		return SQL
+	}
+	var builder strings.Builder
+	quote := byte(0)
+	for i := 0; i < len(SQL); i++ {
+		c := SQL[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' && i > 0 && isPathChar(SQL[i-1]):
+			if end := indexEnd(SQL, i+1); end != -1 {
+				builder.WriteByte(':')
+				builder.WriteString(SQL[i+1 : end])
+				i = end
+				continue
+			}
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String()
+}
+
+func indexEnd(SQL string, offset int) int {
+	for i := offset; i < len(SQL); i++ {
+		switch c := SQL[i]; {
+		case c == ']':
+			if i == offset {
+				return -1
+			}
+			return i
+		case c < '0' || c > '9':
+			return -1
+		}
+	}
+	return -1
+}
+
+func unescapeIndexes(name string) string {
+	if strings.IndexByte(name, ':') == -1 {
+		return name
+	}
+	var builder strings.Builder
+	quote := byte(0)
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ':' && i > 0 && isPathChar(name[i-1]) && i+1 < len(name) && isDigit(name[i+1]):
+			end := i + 1
+			for end < len(name) && isDigit(name[end]) {
+				end++
+			}
+			builder.WriteByte('[')
+			builder.WriteString(name[i+1 : end])
+			builder.WriteByte(']')
+			i = end - 1
+			continue
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String()
+}
+
+func isPathChar(c byte) bool {
+	return c == '_' || c == ']' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,100 @@
+package exec
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strings"
+)
+
+const (
+	//ReturningAllNew returns all item attributes after the write (ALL_NEW)
+	ReturningAllNew = "ALL NEW"
+	//ReturningAllOld returns all item attributes before the write (ALL_OLD)
+	ReturningAllOld = "ALL OLD"
+	//ReturningModifiedNew returns updated attributes after the write (UPDATED_NEW)
+	ReturningModifiedNew = "MODIFIED NEW"
+	//ReturningModifiedOld returns updated attributes before the write (UPDATED_OLD)
+	ReturningModifiedOld = "MODIFIED OLD"
+)
+
+var returningModes = []string{ReturningAllNew, ReturningAllOld, ReturningModifiedNew, ReturningModifiedOld}
+
+//Returning represents write statement RETURNING clause
+type Returning struct {
+	Mode    string
+	Columns []string
+}
+
+//ParseReturning strips trailing RETURNING [ALL|MODIFIED NEW|OLD] *|column[, ...] clause from SQL
+func ParseReturning(SQL string) (string, *Returning, error) {
+	prefix, clauses := SplitClauses(SQL, "RETURNING")
+	if len(clauses) == 0 {
+		return SQL, nil, nil
+	}
+	if len(clauses) > 1 {
+		return "", nil, fmt.Errorf("%w: RETURNING clause: %v", ErrUnsupported, SQL)
+	}
+	text := clauses[0].Text
+	result := &Returning{}
+	for _, mode := range returningModes {
+		if end := matchWords(text, 0, strings.Fields(mode)); end != -1 {
+			result.Mode = mode
+			text = strings.TrimSpace(text[end:])
+			break
+		}
+	}
+	if text == "" {
+		return "", nil, fmt.Errorf("%w: RETURNING clause, expected * or column list: %v", ErrUnsupported, SQL)
+	}
+	if text != "*" {
+		for _, column := range SplitList(text) {
+			if column == "" || column == "*" {
+				return "", nil, fmt.Errorf("%w: RETURNING column: %v", ErrUnsupported, clauses[0].Text)
+			}
+			result.Columns = append(result.Columns, unescapeIndexes(column))
+		}
+	}
+	return strings.TrimSpace(prefix), result, nil
+}
+
+//IsWildcard returns true if all returned attributes are projected
+func (r *Returning) IsWildcard() bool {
+	return len(r.Columns) == 0
+}
+
+func (r *Returning) init(defaultMode string, supported ...string) error {
+	if r.Mode == "" {
+		r.Mode = defaultMode
+	}
+	for _, mode := range supported {
+		if mode == r.Mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: RETURNING %v, supported: %v", ErrUnsupported, r.Mode, strings.Join(supported, ", "))
+}
+
+//initReturning adds returned attributes to execution type
+func (e *Execution) initReturning(desc *types.TableDescription, defaultMode string, supported ...string) error {
+	if e.Returning == nil {
+		return nil
+	}
+	if err := e.Returning.init(defaultMode, supported...); err != nil {
+		return err
+	}
+	rowType := e.Type
+	for _, key := range desc.KeySchema {
+		rowType.Keys[*key.AttributeName] = key.KeyType
+	}
+	if e.Returning.IsWildcard() {
+		rowType.Wildcard = true
+		e.buildWildcardType(desc, rowType)
+		return nil
+	}
+	attrTypes := buildAttributeTypes(desc)
+	for _, column := range e.Returning.Columns {
+		rowType.Add(column, "", attrTypes[column], false)
+	}
+	rowType.sealed = true
+	return nil
+}
@@ -0,0 +1,230 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strings"
+	"time"
+)
+
+const (
+	informationSchema = "information_schema"
+	schemaCatalog     = "def"
+)
+
+var schemaTables = map[string][]MetaColumn{
+	"tables": {{"TABLE_CATALOG", "S"}, {"TABLE_SCHEMA", "S"}, {"TABLE_NAME", "S"}, {"TABLE_TYPE", "S"}, {"TABLE_ROWS", "N"},
+		{"DATA_LENGTH", "N"}, {"CREATE_TIME", "S"}, {"TABLE_STATUS", "S"}, {"BILLING_MODE", "S"}},
+	"columns": {{"TABLE_CATALOG", "S"}, {"TABLE_SCHEMA", "S"}, {"TABLE_NAME", "S"}, {"COLUMN_NAME", "S"}, {"ORDINAL_POSITION", "N"},
+		{"IS_NULLABLE", "S"}, {"DATA_TYPE", "S"}, {"COLUMN_KEY", "S"}, {"ATTRIBUTE_TYPE", "S"}},
+	"statistics": {{"TABLE_CATALOG", "S"}, {"TABLE_SCHEMA", "S"}, {"TABLE_NAME", "S"}, {"NON_UNIQUE", "N"}, {"INDEX_NAME", "S"},
+		{"SEQ_IN_INDEX", "N"}, {"COLUMN_NAME", "S"}, {"INDEX_TYPE", "S"}, {"KEY_TYPE", "S"}, {"PROJECTION", "S"}, {"STATUS", "S"}},
+}
+
+var schemaDataTypes = map[string]string{"S": "varchar", "N": "decimal", "B": "varbinary"}
+
+//IsSchemaQuery returns true if SQL selects from information_schema virtual table
+func IsSchemaQuery(SQL string) bool {
+	_, clauses := SplitClauses(SQL, "FROM")
+	if len(clauses) == 0 {
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(clauses[0].Text), informationSchema+".")
+}
+
+//NewSchemaQuery creates information_schema tables, columns or statistics query execution, schema is reported as TABLE_SCHEMA
+func NewSchemaQuery(SQL string, schema string) (*Execution, error) {
+	prefix, clauses := SplitClauses(strings.TrimRight(strings.TrimSpace(SQL), ";"), "SELECT", "FROM", "WHERE", "ORDER BY", "LIMIT")
+	if strings.TrimSpace(prefix) != "" || len(clauses) < 2 || clauses[0].Keyword != "SELECT" || clauses[1].Keyword != "FROM" {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, SQL)
+	}
+	from := strings.Fields(clauses[1].Text)
+	if len(from) == 0 || !strings.HasPrefix(strings.ToLower(from[0]), informationSchema+".") {
+		return nil, fmt.Errorf("%w: expected %v table: %v", ErrUnsupported, informationSchema, SQL)
+	}
+	table := strings.ToLower(strings.Trim(from[0][len(informationSchema)+1:], "\"`"))
+	source, ok := schemaTables[table]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v.%v, supported: tables, columns, statistics", ErrUnsupported, informationSchema, table)
+	}
+	aQuery := &virtualQuery{name: informationSchema + "." + table, table: table, schema: schema, source: source, limit: -1}
+	return newVirtualExecution(KindSchema, informationSchema+"."+table, aQuery, clauses, 0)
+}
+
+//SchemaItems returns information_schema rows for supplied table descriptions filtered, sorted and projected client side
+func (e *Execution) SchemaItems(descs []*types.TableDescription, args []driver.NamedValue) ([]map[string]types.AttributeValue, error) {
+	var rows []map[string]interface{}
+	for _, desc := range descs {
+		rows = append(rows, schemaRows(e.virtual, desc)...)
+	}
+	return e.virtual.items(rows, args)
+}
+
+//SchemaTables returns table names restricted by TABLE_NAME = or IN criteria, or nil if criteria do not restrict table names,
+//thus only matching tables have to be described
+func (e *Execution) SchemaTables(args []driver.NamedValue) ([]string, error) {
+	if e.virtual == nil || e.virtual.filter == nil {
+		return nil, nil
+	}
+	names, restricted, err := tableNames(e.virtual.filter, args)
+	if err != nil || !restricted {
+		return nil, err
+	}
+	var result = make([]string, 0, len(names))
+	unique := map[string]bool{}
+	for _, name := range names {
+		if !unique[name] {
+			unique[name] = true
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+//tableNames returns TABLE_NAME values matched by condition, restricted is false if any table can match
+func tableNames(cond condition, args []driver.NamedValue) (names []string, restricted bool, err error) {
+	switch actual := cond.(type) {
+	case *logicalCond:
+		x, xRestricted, err := tableNames(actual.x, args)
+		if err != nil {
+			return nil, false, err
+		}
+		y, yRestricted, err := tableNames(actual.y, args)
+		if err != nil {
+			return nil, false, err
+		}
+		switch {
+		case actual.op == "OR" && xRestricted && yRestricted:
+			return append(x, y...), true, nil
+		case actual.op == "AND" && xRestricted && yRestricted:
+			for _, name := range x {
+				for _, candidate := range y {
+					if name == candidate {
+						names = append(names, name)
+					}
+				}
+			}
+			return names, true, nil
+		case actual.op == "AND" && xRestricted:
+			return x, true, nil
+		case actual.op == "AND" && yRestricted:
+			return y, true, nil
+		}
+	case *compareCond:
+		if actual.op != "=" {
+			return nil, false, nil
+		}
+		if isTableName(actual.x) {
+			return tableNameValues(args, actual.y)
+		}
+		if isTableName(actual.y) {
+			return tableNameValues(args, actual.x)
+		}
+	case *inCond:
+		if !actual.not && isTableName(actual.x) {
+			return tableNameValues(args, actual.list...)
+		}
+	}
+	return nil, false, nil
+}
+
+func isTableName(cond condition) bool {
+	column, ok := cond.(*columnCond)
+	return ok && column.name == "TABLE_NAME"
+}
+
+//tableNameValues returns literal or placeholder values, restricted is false for other operands
+func tableNameValues(args []driver.NamedValue, conds ...condition) ([]string, bool, error) {
+	var result []string
+	for _, cond := range conds {
+		switch cond.(type) {
+		case *literalCond, *paramCond:
+		default:
+			return nil, false, nil
+		}
+		value, err := cond.eval(nil, args)
+		if err != nil {
+			return nil, false, err
+		}
+		if value != nil {
+			result = append(result, toString(value))
+		}
+	}
+	return result, true, nil
+}
+
+//schemaRows returns information_schema rows for table description
+func schemaRows(q *virtualQuery, desc *types.TableDescription) []map[string]interface{} {
+	table := stringValue(desc.TableName)
+	newRow := func() map[string]interface{} {
+		return map[string]interface{}{"TABLE_CATALOG": schemaCatalog, "TABLE_SCHEMA": q.schema, "TABLE_NAME": table}
+	}
+	var result []map[string]interface{}
+	switch q.table {
+	case "tables":
+		row := newRow()
+		row["TABLE_TYPE"] = "BASE TABLE"
+		row["TABLE_ROWS"] = intValue(desc.ItemCount)
+		row["DATA_LENGTH"] = intValue(desc.TableSizeBytes)
+		row["CREATE_TIME"] = nil
+		if desc.CreationDateTime != nil {
+			row["CREATE_TIME"] = desc.CreationDateTime.UTC().Format(time.RFC3339)
+		}
+		row["TABLE_STATUS"] = string(desc.TableStatus)
+		row["BILLING_MODE"] = billingMode(desc)
+		result = append(result, row)
+	case "columns":
+		keys := map[string]string{}
+		for _, index := range tableIndexes(desc) {
+			for _, key := range index.keys {
+				if _, ok := keys[*key.AttributeName]; !ok {
+					keys[*key.AttributeName] = "MUL"
+				}
+				if index.kind == indexPrimary {
+					keys[*key.AttributeName] = "PRI"
+				}
+			}
+		}
+		for i, attr := range desc.AttributeDefinitions {
+			row := newRow()
+			name := *attr.AttributeName
+			row["COLUMN_NAME"] = name
+			row["ORDINAL_POSITION"] = i + 1
+			row["IS_NULLABLE"] = "YES"
+			if keys[name] == "PRI" {
+				row["IS_NULLABLE"] = "NO"
+			}
+			row["DATA_TYPE"] = schemaDataTypes[string(attr.AttributeType)]
+			row["COLUMN_KEY"] = keys[name]
+			row["ATTRIBUTE_TYPE"] = string(attr.AttributeType)
+			result = append(result, row)
+		}
+	case "statistics":
+		for _, index := range tableIndexes(desc) {
+			for i, key := range index.keys {
+				row := newRow()
+				row["NON_UNIQUE"] = 1
+				if index.kind == indexPrimary {
+					row["NON_UNIQUE"] = 0
+				}
+				row["INDEX_NAME"] = index.name
+				row["SEQ_IN_INDEX"] = i + 1
+				row["COLUMN_NAME"] = *key.AttributeName
+				row["INDEX_TYPE"] = index.kind
+				row["KEY_TYPE"] = string(key.KeyType)
+				row["PROJECTION"] = index.projection
+				row["STATUS"] = index.status
+				result = append(result, row)
+			}
+		}
+	}
+	return result
+}
+
+func intValue(value *int64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return int(*value)
+}
@@ -0,0 +1,209 @@
+package exec_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dyndb/internal/exec"
+	"testing"
+)
+
+func TestExecution_SchemaItems(t *testing.T) {
+	itemCount := int64(3)
+	descs := []*types.TableDescription{
+		{
+			TableName: stringPtr("Publication"),
+			ItemCount: &itemCount,
+			AttributeDefinitions: []types.AttributeDefinition{
+				{AttributeName: stringPtr("ISBN"), AttributeType: types.ScalarAttributeTypeS},
+				{AttributeName: stringPtr("Published"), AttributeType: types.ScalarAttributeTypeN},
+				{AttributeName: stringPtr("Name"), AttributeType: types.ScalarAttributeTypeS},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: stringPtr("ISBN"), KeyType: types.KeyTypeHash},
+				{AttributeName: stringPtr("Published"), KeyType: types.KeyTypeRange},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+				{
+					IndexName:  stringPtr("ByName"),
+					KeySchema:  []types.KeySchemaElement{{AttributeName: stringPtr("Name"), KeyType: types.KeyTypeHash}},
+					Projection: &types.Projection{ProjectionType: types.ProjectionTypeKeysOnly},
+				},
+			},
+		},
+		{
+			TableName: stringPtr("Users"),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{AttributeName: stringPtr("Id"), AttributeType: types.ScalarAttributeTypeN},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: stringPtr("Id"), KeyType: types.KeyTypeHash},
+			},
+		},
+	}
+	var testCases = []struct {
+		description string
+		SQL         string
+		args        []driver.NamedValue
+		expect      []map[string]interface{}
+		hasError    bool
+		unsupported bool
+	}{
+		{
+			description: "tables",
+			SQL:         "SELECT table_schema, table_name, table_rows FROM information_schema.tables ORDER BY table_name DESC",
+			expect: []map[string]interface{}{
+				{"table_schema": "us-west-1", "table_name": "Users", "table_rows": nil},
+				{"table_schema": "us-west-1", "table_name": "Publication", "table_rows": 3.0},
+			},
+		},
+		{
+			description: "columns with precedence, placeholders and alias",
+			SQL: `SELECT c.COLUMN_NAME AS name, c.DATA_TYPE, COLUMN_KEY FROM INFORMATION_SCHEMA.COLUMNS c
+					WHERE table_name = ? AND column_key = 'PRI' OR table_name = 'Publication' AND NOT begins_with(column_name, 'IS')
+					ORDER BY table_name, ordinal_position`,
+			args: []driver.NamedValue{{Ordinal: 1, Value: "Users"}},
+			expect: []map[string]interface{}{
+				{"name": "Published", "DATA_TYPE": "decimal", "COLUMN_KEY": "PRI"},
+				{"name": "Name", "DATA_TYPE": "varchar", "COLUMN_KEY": "MUL"},
+				{"name": "Id", "DATA_TYPE": "decimal", "COLUMN_KEY": "PRI"},
+			},
+		},
+		{
+			description: "statistics",
+			SQL:         "SELECT INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME, NON_UNIQUE FROM information_schema.statistics WHERE TABLE_NAME IN ('Publication') AND SEQ_IN_INDEX BETWEEN 1 AND 2 LIMIT 2",
+			expect: []map[string]interface{}{
+				{"INDEX_NAME": "PRIMARY", "SEQ_IN_INDEX": 1.0, "COLUMN_NAME": "ISBN", "NON_UNIQUE": 0.0},
+				{"INDEX_NAME": "PRIMARY", "SEQ_IN_INDEX": 2.0, "COLUMN_NAME": "Published", "NON_UNIQUE": 0.0},
+			},
+		},
+		{
+			description: "null, not in and contains",
+			SQL:         "SELECT TABLE_NAME FROM information_schema.tables WHERE TABLE_ROWS IS NULL OR TABLE_NAME NOT IN ('Users') AND contains(TABLE_NAME, 'cat')",
+			expect: []map[string]interface{}{
+				{"TABLE_NAME": "Publication"},
+				{"TABLE_NAME": "Users"},
+			},
+		},
+		{
+			description: "unsupported function",
+			SQL:         "SELECT TABLE_NAME FROM information_schema.tables WHERE size(TABLE_NAME) = 1",
+			hasError:    true,
+		},
+		{
+			description: "implicit alias ordered by alias",
+			SQL:         "SELECT t.TABLE_NAME tbl, TABLE_ROWS AS n FROM information_schema.tables t ORDER BY tbl DESC",
+			expect: []map[string]interface{}{
+				{"tbl": "Users", "n": nil},
+				{"tbl": "Publication", "n": 3.0},
+			},
+		},
+		{
+			description: "unsupported projection expression",
+			SQL:         "SELECT TABLE_ROWS + 1 AS n FROM information_schema.tables",
+			hasError:    true,
+			unsupported: true,
+		},
+		{
+			description: "unsupported projection function",
+			SQL:         "SELECT upper(TABLE_NAME) FROM information_schema.tables",
+			hasError:    true,
+			unsupported: true,
+		},
+		{
+			description: "unsupported order by expression",
+			SQL:         "SELECT TABLE_NAME FROM information_schema.tables ORDER BY size(TABLE_NAME) DESC",
+			hasError:    true,
+			unsupported: true,
+		},
+		{
+			description: "unknown column",
+			SQL:         "SELECT foo FROM information_schema.tables",
+			hasError:    true,
+		},
+		{
+			description: "unsupported table",
+			SQL:         "SELECT * FROM information_schema.views",
+			hasError:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.True(t, exec.IsSchemaQuery(testCase.SQL), testCase.description)
+		execution, err := exec.NewSchemaQuery(testCase.SQL, "us-west-1")
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			if testCase.unsupported {
+				assert.True(t, errors.Is(err, exec.ErrUnsupported), testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, len(testCase.args), execution.NumInput(), testCase.description)
+		items, err := execution.SchemaItems(descs, testCase.args)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []map[string]interface{}
+		assert.Nil(t, attributevalue.UnmarshalListOfMaps(items, &actual), testCase.description)
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
+
+func TestExecution_SchemaTables(t *testing.T) {
+	var testCases = []struct {
+		description string
+		SQL         string
+		args        []driver.NamedValue
+		expect      []string
+	}{
+		{
+			description: "no criteria",
+			SQL:         "SELECT * FROM information_schema.tables",
+		},
+		{
+			description: "equal placeholder",
+			SQL:         "SELECT * FROM information_schema.columns WHERE TABLE_NAME = ? AND COLUMN_KEY = 'PRI'",
+			args:        []driver.NamedValue{{Ordinal: 1, Value: "Users"}},
+			expect:      []string{"Users"},
+		},
+		{
+			description: "in list",
+			SQL:         "SELECT * FROM information_schema.columns WHERE table_name IN ('Users', ?, 'Users')",
+			args:        []driver.NamedValue{{Ordinal: 1, Value: "Publication"}},
+			expect:      []string{"Users", "Publication"},
+		},
+		{
+			description: "or of table names",
+			SQL:         "SELECT * FROM information_schema.statistics WHERE TABLE_NAME = 'Users' OR TABLE_NAME = 'Publication'",
+			expect:      []string{"Users", "Publication"},
+		},
+		{
+			description: "contradicting table names",
+			SQL:         "SELECT * FROM information_schema.tables WHERE TABLE_NAME = 'Users' AND TABLE_NAME = 'Publication'",
+			expect:      []string{},
+		},
+		{
+			description: "or with other column",
+			SQL:         "SELECT * FROM information_schema.tables WHERE TABLE_NAME = 'Users' OR TABLE_ROWS > 1",
+		},
+		{
+			description: "not in",
+			SQL:         "SELECT * FROM information_schema.tables WHERE TABLE_NAME NOT IN ('Users')",
+		},
+	}
+
+	for _, testCase := range testCases {
+		execution, err := exec.NewSchemaQuery(testCase.SQL, "us-west-1")
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		actual, err := execution.SchemaTables(testCase.args)
+		assert.Nil(t, err, testCase.description)
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
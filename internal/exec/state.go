@@ -22,47 +22,49 @@ type State struct {
 
 //QueryParameters returns query params
 func (s *State) QueryParameters() ([]types.AttributeValue, error) {
-	var result []types.AttributeValue
-	if len(s.Type.Criteria) == 0 {
-		return result, nil
-	}
-	pos := 0
-	for _, param := range s.Type.Criteria {
-		if param.Kind == ParameterKindPlaceholder {
-			arg := s.Args[pos].Value
-			pos++
-			attrValue, err := Encode(arg)
-			if err != nil {
-				return nil, fmt.Errorf("failed to encode args: %T(%v), %w", arg, arg, err)
-			}
-			result = append(result, attrValue)
-		}
-	}
-	return result, nil
+	return s.encodeParameters(nil, s.Type.Criteria)
 }
 
 //ProjectionListParameters returns list attributes
 func (s *State) ProjectionListParameters() ([]types.AttributeValue, error) {
-	var result []types.AttributeValue
-	if len(s.Type.List) == 0 {
-		return result, nil
+	return s.encodeParameters(nil, s.Type.List)
+}
+
+//Parameters returns list followed by criteria attributes
+func (s *State) Parameters() ([]types.AttributeValue, error) {
+	result, err := s.encodeParameters(nil, s.Type.List)
+	if err != nil {
+		return nil, err
 	}
-	pos := 0
+	return s.encodeParameters(result, s.Type.Criteria)
+}
 
-	for _, param := range s.Type.List {
-		if param.Kind == ParameterKindPlaceholder {
-			arg := s.Args[pos].Value
-			pos++
-			attrValue, err := Encode(arg)
-			if err != nil {
-				return nil, fmt.Errorf("failed to encode args: %T(%v), %w", arg, arg, err)
-			}
-			result = append(result, attrValue)
+func (s *State) encodeParameters(result []types.AttributeValue, params []*Parameter) ([]types.AttributeValue, error) {
+	for _, param := range params {
+		if param.Kind != ParameterKindPlaceholder {
+			continue
+		}
+		attrValue, err := s.encodeParameter(param)
+		if err != nil {
+			return nil, err
 		}
+		result = append(result, attrValue)
 	}
 	return result, nil
 }
 
+func (s *State) encodeParameter(param *Parameter) (types.AttributeValue, error) {
+	if param.Pos >= len(s.Args) {
+		return nil, fmt.Errorf("%w: missing argument for parameter %v: expected %v, but had %v", ErrInvalid, param.Name, s.Type.BindingLen, len(s.Args))
+	}
+	arg := s.Args[param.Pos].Value
+	attrValue, err := Encode(arg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to encode args: %T(%v), %v", ErrInvalid, arg, arg, err)
+	}
+	return attrValue, nil
+}
+
 //Values returns values
 func (s *State) Values(value interface{}, parameters []*Parameter) ([]interface{}, error) {
 	var result = make([]interface{}, len(parameters))
@@ -92,14 +94,32 @@ func (s *State) NKeys() int {
 
 // UnmarshalJSONObject implements gojay's UnmarshalerJSONObject
 func (s *State) UnmarshalJSONObject(dec *gojay.Decoder, k string) (err error) {
+	if path := s.Type.Path(k); path != nil {
+		return s.decodePath(dec, path)
+	}
 	pos, ok := s.Type.fields[k]
 	if !ok {
+		if s.Type.Wildcard || s.Type.sealed {
+			return nil
+		}
 		return fmt.Errorf("unknown field: %s", k)
 	}
 	s.fieldUnmarshaler.field = &s.Type.Fields[pos]
 	return dec.Object(&s.fieldUnmarshaler)
 }
 
+func (s *State) decodePath(dec *gojay.Decoder, path *Path) error {
+	if !path.IsProjected() {
+		return dec.Object(&pathUnmarshaler{values: s.Fields, fields: s.Type.Fields, path: path})
+	}
+	s.fieldUnmarshaler.field = &s.Type.Fields[path.Pos]
+	if err := dec.Object(&s.fieldUnmarshaler); err != nil {
+		return err
+	}
+	extractPath(s.Fields, path, s.Fields[path.Pos])
+	return nil
+}
+
 //Init initialise state
 func (s *State) Init() error {
 	if !atomic.CompareAndSwapInt32(&s.initialised, 0, 1) {
@@ -0,0 +1,151 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	streams "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"strings"
+	"time"
+)
+
+var streamColumns = []MetaColumn{{"EventID", "S"}, {"EventName", "S"}, {"Keys", "M"}, {"NewImage", "M"}, {"OldImage", "M"},
+	{"SequenceNumber", "S"}, {"ShardId", "S"}, {"CreatedAt", "S"}, {"Checkpoint", "S"}}
+
+//StreamCheckpoint represents resumable stream position, last read sequence number keyed by shard id
+type StreamCheckpoint map[string]string
+
+//IsStreamQuery returns true if SQL selects from STREAM(table) source
+func IsStreamQuery(SQL string) bool {
+	_, clauses := SplitClauses(SQL, "FROM")
+	if len(clauses) == 0 {
+		return false
+	}
+	from := strings.ToLower(clauses[0].Text)
+	return strings.HasPrefix(from, "stream(") || strings.HasPrefix(from, "stream (")
+}
+
+//NewStreamQuery creates SELECT ... FROM STREAM(table [, checkpoint]) execution reading table change stream records
+func NewStreamQuery(SQL string) (*Execution, error) {
+	prefix, clauses := SplitClauses(strings.TrimRight(strings.TrimSpace(SQL), ";"), "SELECT", "FROM", "WHERE", "ORDER BY", "LIMIT")
+	if strings.TrimSpace(prefix) != "" || len(clauses) < 2 || clauses[0].Keyword != "SELECT" || clauses[1].Keyword != "FROM" {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, SQL)
+	}
+	from := strings.TrimSpace(clauses[1].Text)
+	begin, end := strings.IndexByte(from, '('), strings.LastIndexByte(from, ')')
+	if begin == -1 || end < begin {
+		return nil, fmt.Errorf("%w: expected STREAM(table [, checkpoint]): %v", ErrInvalid, from)
+	}
+	args := SplitList(from[begin+1 : end])
+	if len(args) == 0 || len(args) > 2 || args[0] == "" {
+		return nil, fmt.Errorf("%w: expected STREAM(table [, checkpoint]): %v", ErrInvalid, from)
+	}
+	table := tableName(args[0])
+	aQuery := &virtualQuery{name: "STREAM(" + table + ")", table: table, source: streamColumns, limit: -1}
+	params := 0
+	if len(args) == 2 {
+		if args[1] == "?" {
+			params = 1
+		} else {
+			aQuery.checkpoint = strings.Trim(args[1], "'\"")
+		}
+	}
+	for _, clause := range clauses[2:] {
+		if clause.Keyword == "ORDER BY" {
+			return nil, fmt.Errorf("%w: ORDER BY in %v, records are returned in stream order", ErrUnsupported, aQuery.name)
+		}
+	}
+	return newVirtualExecution(KindStream, table, aQuery, clauses, params)
+}
+
+//StreamCheckpoint returns checkpoint to resume reading from, it is supplied as STREAM second argument
+func (e *Execution) StreamCheckpoint(args []driver.NamedValue) (StreamCheckpoint, error) {
+	text := e.virtual.checkpoint
+	if e.Type.NumInput() > 0 && len(args) > 0 && args[0].Value != nil {
+		value, ok := args[0].Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected string checkpoint, but had %T", ErrInvalid, args[0].Value)
+		}
+		text = value
+	}
+	return DecodeStreamCheckpoint(text)
+}
+
+//StreamLimit returns max number of stream rows or -1 when unlimited
+func (e *Execution) StreamLimit() int {
+	return e.virtual.limit
+}
+
+//StreamItems returns stream records as rows filtered and projected client side, checkpoint is advanced by every record
+func (e *Execution) StreamItems(shardID string, records []streams.Record, checkpoint StreamCheckpoint, args []driver.NamedValue) ([]map[string]types.AttributeValue, error) {
+	var rows []map[string]interface{}
+	for _, record := range records {
+		if record.Dynamodb == nil {
+			continue
+		}
+		data := record.Dynamodb
+		row := map[string]interface{}{"EventID": stringValue(record.EventID), "EventName": string(record.EventName),
+			"SequenceNumber": stringValue(data.SequenceNumber), "ShardId": shardID, "CreatedAt": nil}
+		for name, image := range map[string]map[string]streams.AttributeValue{"Keys": data.Keys, "NewImage": data.NewImage, "OldImage": data.OldImage} {
+			row[name] = nil
+			if image == nil {
+				continue
+			}
+			value, err := attributevalue.FromDynamoDBStreamsMap(image)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert %v: %w", name, err)
+			}
+			row[name] = &types.AttributeValueMemberM{Value: value}
+		}
+		if data.ApproximateCreationDateTime != nil {
+			row["CreatedAt"] = data.ApproximateCreationDateTime.UTC().Format(time.RFC3339)
+		}
+		if data.SequenceNumber != nil {
+			checkpoint[shardID] = *data.SequenceNumber
+		}
+		row["Checkpoint"] = checkpoint.Encode()
+		rows = append(rows, row)
+	}
+	return e.virtual.items(rows, args)
+}
+
+//DecodeStreamCheckpoint decodes checkpoint, empty text represents beginning of the stream
+func DecodeStreamCheckpoint(text string) (StreamCheckpoint, error) {
+	result := StreamCheckpoint{}
+	if text == "" {
+		return result, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(text)
+	if err == nil {
+		err = json.Unmarshal(data, &result)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid stream checkpoint: %v", ErrInvalid, err)
+	}
+	return result, nil
+}
+
+//Encode encodes checkpoint
+func (c StreamCheckpoint) Encode() string {
+	if len(c) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(map[string]string(c))
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+//Retain removes shards other than supplied ones, expired shards do not grow checkpoint
+func (c StreamCheckpoint) Retain(shardIDs []string) {
+	index := make(map[string]bool, len(shardIDs))
+	for _, shardID := range shardIDs {
+		index[shardID] = true
+	}
+	for shardID := range c {
+		if !index[shardID] {
+			delete(c, shardID)
+		}
+	}
+}
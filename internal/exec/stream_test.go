@@ -0,0 +1,118 @@
+package exec_test
+
+import (
+	"database/sql/driver"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/francoispqt/gojay"
+	"github.com/stretchr/testify/assert"
+	ndynamodb "github.com/viant/dyndb/internal/dynamodb"
+	"github.com/viant/dyndb/internal/exec"
+	"testing"
+)
+
+func TestExecution_StreamItems(t *testing.T) {
+	records := []types.Record{
+		{
+			EventID:   stringPtr("e1"),
+			EventName: types.OperationTypeInsert,
+			Dynamodb: &types.StreamRecord{
+				SequenceNumber: stringPtr("100"),
+				Keys:           map[string]types.AttributeValue{"Id": &types.AttributeValueMemberN{Value: "1"}},
+				NewImage: map[string]types.AttributeValue{"Id": &types.AttributeValueMemberN{Value: "1"},
+					"Name": &types.AttributeValueMemberS{Value: "Bob"}},
+			},
+		},
+		{
+			EventID:   stringPtr("e2"),
+			EventName: types.OperationTypeRemove,
+			Dynamodb: &types.StreamRecord{
+				SequenceNumber: stringPtr("200"),
+				Keys:           map[string]types.AttributeValue{"Id": &types.AttributeValueMemberN{Value: "2"}},
+				OldImage:       map[string]types.AttributeValue{"Id": &types.AttributeValueMemberN{Value: "2"}},
+			},
+		},
+	}
+	var testCases = []struct {
+		description string
+		SQL         string
+		args        []driver.NamedValue
+		checkpoint  exec.StreamCheckpoint
+		expect      [][]driver.Value
+		hasError    bool
+	}{
+		{
+			description: "all records",
+			SQL:         "SELECT eventName, Keys, NewImage, OldImage FROM STREAM(Users)",
+			expect: [][]driver.Value{
+				{"INSERT", map[string]interface{}{"Id": 1}, map[string]interface{}{"Id": 1, "Name": "Bob"}, nil},
+				{"REMOVE", map[string]interface{}{"Id": 2}, nil, map[string]interface{}{"Id": 2}},
+			},
+		},
+		{
+			description: "checkpoint placeholder with filter",
+			SQL:         "SELECT EventID, SequenceNumber FROM STREAM(Users, ?) WHERE EventName = ? LIMIT 1",
+			args:        []driver.NamedValue{{Ordinal: 1, Value: exec.StreamCheckpoint{"shard-0": "50"}.Encode()}, {Ordinal: 2, Value: "REMOVE"}},
+			checkpoint:  exec.StreamCheckpoint{"shard-0": "50"},
+			expect:      [][]driver.Value{{"e2", "200"}},
+		},
+		{
+			description: "order by",
+			SQL:         "SELECT * FROM STREAM(Users) ORDER BY EventID",
+			hasError:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.True(t, exec.IsStreamQuery(testCase.SQL), testCase.description)
+		execution, err := exec.NewStreamQuery(testCase.SQL)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, "Users", execution.Table, testCase.description)
+		assert.EqualValues(t, len(testCase.args), execution.NumInput(), testCase.description)
+		checkpoint, err := execution.StreamCheckpoint(testCase.args)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if testCase.checkpoint != nil {
+			assert.EqualValues(t, testCase.checkpoint, checkpoint, testCase.description)
+		}
+		items, err := execution.StreamItems("shard-0", records, checkpoint, testCase.args)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, exec.StreamCheckpoint{"shard-0": "200"}, checkpoint, testCase.description)
+		state := execution.NewQueryState(testCase.args)
+		output := ndynamodb.NewExecuteStatementOutput(state.Type)
+		if err = output.Load(items...); !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if !assert.Nil(t, state.Init(), testCase.description) {
+			continue
+		}
+		var actual [][]driver.Value
+		for _, row := range output.Rows {
+			values := make([]driver.Value, len(state.Type.Columns))
+			state.SetDest(values)
+			err = gojay.Unmarshal(output.Data[row.Begin:row.End], state)
+			if err == nil {
+				err = state.Reconcile()
+			}
+			if !assert.Nil(t, err, testCase.description) {
+				break
+			}
+			actual = append(actual, values)
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+
+	decoded, err := exec.DecodeStreamCheckpoint(exec.StreamCheckpoint{"shard-0": "200"}.Encode())
+	assert.Nil(t, err)
+	assert.EqualValues(t, exec.StreamCheckpoint{"shard-0": "200"}, decoded)
+	_, err = exec.DecodeStreamCheckpoint("not a checkpoint")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,185 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/francoispqt/gojay"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+type (
+	//StructDecoder decodes top level scalar attributes directly into struct fields,
+	//other columns (functions, defaults, document paths, lists, maps) are decoded into state values
+	StructDecoder struct {
+		state   *State
+		fields  map[string]*structField
+		direct  []*structField
+		columns map[int]bool
+		ptr     unsafe.Pointer
+		setter  structSetter
+	}
+
+	structField struct {
+		*xunsafe.Field
+		kind reflect.Kind
+	}
+
+	//structSetter sets attribute value to struct field pointer
+	structSetter struct {
+		field *structField
+		ptr   unsafe.Pointer
+	}
+)
+
+//NewStructDecoder creates struct decoder for struct fields by column position
+func NewStructDecoder(state *State, fields map[int]*xunsafe.Field) *StructDecoder {
+	result := &StructDecoder{state: state, fields: map[string]*structField{}, columns: map[int]bool{}}
+	for column, field := range fields {
+		attribute := state.Type.directField(column)
+		if attribute == nil || !isDirectKind(attribute.Type, field.Type.Kind()) {
+			continue
+		}
+		directField := &structField{Field: field, kind: field.Type.Kind()}
+		result.fields[attribute.Name] = directField
+		result.direct = append(result.direct, directField)
+		result.columns[column] = true
+	}
+	return result
+}
+
+//IsDirect returns true if column is decoded directly into struct field
+func (d *StructDecoder) IsDirect(column int) bool {
+	return d.columns[column]
+}
+
+//Decode decodes row into struct pointer direct fields, other columns are decoded into dest values
+func (d *StructDecoder) Decode(data []byte, ptr unsafe.Pointer, dest []driver.Value) error {
+	for _, field := range d.direct {
+		setZero(field.Pointer(ptr), field.kind)
+	}
+	d.ptr = ptr
+	d.state.SetDest(dest)
+	if err := gojay.Unmarshal(data, d); err != nil {
+		return err
+	}
+	return d.state.Reconcile()
+}
+
+//NKeys returns keys
+func (d *StructDecoder) NKeys() int {
+	return 0
+}
+
+// UnmarshalJSONObject implements gojay's UnmarshalerJSONObject
+func (d *StructDecoder) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
+	field, ok := d.fields[k]
+	if !ok {
+		return d.state.UnmarshalJSONObject(dec, k)
+	}
+	d.setter.field, d.setter.ptr = field, field.Pointer(d.ptr)
+	if err := dec.Object(&d.setter); err != nil {
+		return fmt.Errorf("failed to set %v: %w", field.Name, err)
+	}
+	return nil
+}
+
+// NKeys returns the number of keys to unmarshal
+func (s *structSetter) NKeys() int { return 0 }
+
+// UnmarshalJSONObject implements gojay's UnmarshalerJSONObject, numbers are parsed from attribute text
+func (s *structSetter) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
+	if k == "NULL" {
+		setZero(s.ptr, s.field.kind)
+		return nil
+	}
+	literal, err := rawValue(dec)
+	if err != nil {
+		return err
+	}
+	switch s.field.kind {
+	case reflect.String:
+		*(*string)(s.ptr) = string(literal)
+	case reflect.Bool:
+		*(*bool)(s.ptr), err = strconv.ParseBool(string(literal))
+	case reflect.Float64:
+		*(*float64)(s.ptr), err = strconv.ParseFloat(string(literal), 64)
+	case reflect.Float32:
+		var value float64
+		value, err = strconv.ParseFloat(string(literal), 32)
+		*(*float32)(s.ptr) = float32(value)
+	default:
+		var value int64
+		if value, err = strconv.ParseInt(string(literal), 10, 64); err == nil {
+			setInt(s.ptr, s.field.kind, value)
+		}
+	}
+	return err
+}
+
+//directField returns top level attribute field projected as is by the column or nil
+func (t *Type) directField(column int) *Field {
+	if column >= len(t.Columns) {
+		return nil
+	}
+	aColumn := &t.Columns[column]
+	if aColumn.Func != nil || aColumn.DefaultValue != nil || len(aColumn.Fields) != 1 {
+		return nil
+	}
+	field := &t.Fields[aColumn.Fields[0]]
+	if len(field.linked) != 1 || field.Type == nil || strings.ContainsAny(field.Name, ".[") || t.paths[field.Name] != nil {
+		return nil
+	}
+	return field
+}
+
+//isDirectKind returns true if attribute type value can be set to struct field kind without conversion
+func isDirectKind(attrType reflect.Type, kind reflect.Kind) bool {
+	switch attrType.Kind() {
+	case reflect.String:
+		return kind == reflect.String
+	case reflect.Bool:
+		return kind == reflect.Bool
+	case reflect.Float64:
+		return kind == reflect.Float64 || kind == reflect.Float32
+	case reflect.Int:
+		switch kind {
+		case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+			return true
+		}
+	}
+	return false
+}
+
+func setInt(ptr unsafe.Pointer, kind reflect.Kind, value int64) {
+	switch kind {
+	case reflect.Int:
+		*(*int)(ptr) = int(value)
+	case reflect.Int64:
+		*(*int64)(ptr) = value
+	case reflect.Int32:
+		*(*int32)(ptr) = int32(value)
+	case reflect.Int16:
+		*(*int16)(ptr) = int16(value)
+	case reflect.Int8:
+		*(*int8)(ptr) = int8(value)
+	}
+}
+
+func setZero(ptr unsafe.Pointer, kind reflect.Kind) {
+	switch kind {
+	case reflect.String:
+		*(*string)(ptr) = ""
+	case reflect.Bool:
+		*(*bool)(ptr) = false
+	case reflect.Float64:
+		*(*float64)(ptr) = 0
+	case reflect.Float32:
+		*(*float32)(ptr) = 0
+	default:
+		setInt(ptr, kind, 0)
+	}
+}
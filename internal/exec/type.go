@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/francoispqt/gojay"
-	"github.com/viant/sqlparser"
 	"github.com/viant/sqlparser/expr"
 	"github.com/viant/sqlparser/query"
 	"reflect"
+	"sort"
 	"strings"
 	"sync/atomic"
 )
@@ -21,8 +21,11 @@ type (
 		Fields   []Field
 		fields   map[string]int
 		columns  map[string]int
+		paths    map[string]*Path
 		Columns  []Column
 		Keys     map[string]types.KeyType
+		sealed   bool
+		resolved bool
 	}
 
 	//Field represents underlying storage field
@@ -75,11 +78,11 @@ func (o *Columns) init(aQuery *query.Select) error {
 		}
 		switch actual := item.Expr.(type) {
 		case *expr.Call:
-			if fName := sqlparser.Stringify(actual.X); strings.ToLower(fName) == "coalesce" {
+			if fName := stringify(actual.X); strings.ToLower(fName) == "coalesce" {
 				if len(actual.Args) != 2 {
 					return fmt.Errorf("coalesce invalid argsument count")
 				}
-				column.FieldNames = append(column.FieldNames, sqlparser.Stringify(actual.Args[0]))
+				column.FieldNames = append(column.FieldNames, stringify(actual.Args[0]))
 				if literal, ok := actual.Args[1].(*expr.Literal); ok {
 					param := NewLiteral(literal.Value, literal.Kind)
 					column.DefaultValue = param.Value
@@ -88,7 +91,7 @@ func (o *Columns) init(aQuery *query.Select) error {
 		case *expr.Star:
 		case *expr.Ident, *expr.Selector:
 			if column.Name == "" {
-				column.Name = sqlparser.Stringify(actual)
+				column.Name = stringify(actual)
 			}
 		}
 	}
@@ -97,6 +100,7 @@ func (o *Columns) init(aQuery *query.Select) error {
 
 //Column returns a column
 func (t *Type) Column(name string) *Column {
+	name = unescapeIndexes(name)
 	pos, ok := t.columns[name]
 	if ok {
 		return &t.Columns[pos]
@@ -120,26 +124,62 @@ func (t *Type) Add(fName, cName, attrType string, isRequired bool) (*Field, *Col
 	return field, column
 }
 
-func normalizeKey(name string) string {
-	if index := strings.LastIndexByte(name, '.'); index != -1 {
-		return name[index+1:]
-	}
-	return name
-}
-
-//Field returns a filed
+//Field returns a filed, nested document path (Info.Address.City, Tags[0]) is kept as a whole
 func (t *Type) Field(name string) *Field {
-	key := normalizeKey(name)
-	pos, ok := t.fields[key]
+	name = unescapeIndexes(name)
+	pos, ok := t.fields[name]
 	if ok {
 		return &t.Fields[pos]
 	}
 	pos = len(t.Fields)
 	t.Fields = append(t.Fields, Field{Pos: pos, Name: name})
-	t.fields[key] = pos
+	t.fields[name] = pos
+	t.addPath(name, pos)
 	return &t.Fields[pos]
 }
 
+//Discover returns existing or new field, sealed type (initialised wildcard or RETURNING projection) returns nil for unknown fields
+func (t *Type) Discover(name string) *Field {
+	if pos, ok := t.fields[name]; ok {
+		return &t.Fields[pos]
+	}
+	if t.sealed {
+		return nil
+	}
+	return t.Field(name)
+}
+
+//Path returns document path for top level attribute with nested projection or nil
+func (t *Type) Path(name string) *Path {
+	return t.paths[name]
+}
+
+func (t *Type) addPath(name string, pos int) {
+	if strings.IndexByte(name, '.') == -1 && strings.IndexByte(name, '[') == -1 {
+		if root, ok := t.paths[name]; ok {
+			root.Pos = pos
+		}
+		return
+	}
+	elements := ParsePath(name)
+	if len(elements) < 2 {
+		return
+	}
+	rootName := elements[0].Name
+	root, ok := t.paths[rootName]
+	if !ok {
+		root = newPath(rootName)
+		if rootPos, ok := t.fields[rootName]; ok {
+			root.Pos = rootPos
+		}
+		if t.paths == nil {
+			t.paths = map[string]*Path{}
+		}
+		t.paths[rootName] = root
+	}
+	root.add(elements[1:], pos)
+}
+
 //Link links field with column
 func (c *Column) Link(field *Field) {
 	c.Fields = append(c.Fields, field.Pos)
@@ -177,8 +217,69 @@ func (t *Type) Init() error {
 	if !atomic.CompareAndSwapInt32(&t.initialized, 0, 1) {
 		return nil
 	}
+	if t.Wildcard {
+		t.expandWildcard()
+	}
 	t.ensureTypes()
-	return t.ensureDecoders()
+	if err := t.ensureDecoders(); err != nil {
+		return err
+	}
+	t.resolved = true
+	return nil
+}
+
+//IsResolved returns true if type has been initialised, resolved type is no longer modified by decoding
+func (t *Type) IsResolved() bool {
+	return t.resolved
+}
+
+//expandWildcard adds columns for attributes discovered on the sampled pages, keys go first followed by the other attributes sorted by name,
+//attributes first appearing on later pages are skipped as rows columns are already reported
+func (t *Type) expandWildcard() {
+	var names []string
+	for i := range t.Fields {
+		if len(t.Fields[i].linked) == 0 {
+			names = append(names, t.Fields[i].Name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.Column(name).Link(t.Field(name))
+	}
+	t.sealed = true
+}
+
+//Clone returns a type deep copy, query state uses its own copy to discover attributes until the execution type is resolved
+func (t *Type) Clone() *Type {
+	result := &Type{
+		Parameters: t.Parameters,
+		Wildcard:   t.Wildcard,
+		Fields:     make([]Field, len(t.Fields)),
+		fields:     make(map[string]int, len(t.fields)),
+		columns:    make(map[string]int, len(t.columns)),
+		paths:      make(map[string]*Path, len(t.paths)),
+		Columns:    make([]Column, len(t.Columns)),
+		Keys:       t.Keys,
+		sealed:     t.sealed,
+	}
+	for i, field := range t.Fields {
+		field.linked = append([]int{}, field.linked...)
+		result.Fields[i] = field
+	}
+	for i, column := range t.Columns {
+		column.Fields = append([]int{}, column.Fields...)
+		result.Columns[i] = column
+	}
+	for k, v := range t.fields {
+		result.fields[k] = v
+	}
+	for k, v := range t.columns {
+		result.columns[k] = v
+	}
+	for k, v := range t.paths {
+		result.paths[k] = v.clone()
+	}
+	return result
 }
 
 func (t *Type) ensureTypes() {
@@ -10,6 +10,15 @@ type (
 		values []driver.Value
 		field  *Field
 	}
+
+	pathUnmarshaler struct {
+		values []driver.Value
+		fields []Field
+		path   *Path
+		kind   string
+	}
+
+	skipUnmarshaler struct{}
 )
 
 // NKeys returns the number of keys to unmarshal
@@ -17,6 +26,10 @@ func (t *fieldUnmarshaler) NKeys() int { return 0 }
 
 // UnmarshalJSONObject implements gojay's UnmarshalerJSONObject
 func (t *fieldUnmarshaler) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
+	if k == "NULL" {
+		t.values[t.field.Pos] = nil
+		return nil
+	}
 	value, err := t.field.Decoder(dec)
 	if err != nil {
 		return err
@@ -24,3 +37,80 @@ func (t *fieldUnmarshaler) UnmarshalJSONObject(dec *gojay.Decoder, k string) err
 	t.values[t.field.Pos] = value
 	return nil
 }
+
+// NKeys returns the number of keys to unmarshal
+func (p *pathUnmarshaler) NKeys() int { return 0 }
+
+// UnmarshalJSONObject implements gojay's UnmarshalerJSONObject, it descends into M and L attribute values
+func (p *pathUnmarshaler) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
+	if p.kind == "M" {
+		if child := p.path.Key(k); child != nil {
+			return p.decode(dec, child)
+		}
+		return nil
+	}
+	switch k {
+	case "M", "L":
+		nested := &pathUnmarshaler{values: p.values, fields: p.fields, path: p.path, kind: k}
+		if k == "M" {
+			return dec.Object(nested)
+		}
+		return dec.Array(nested)
+	}
+	return nil
+}
+
+// UnmarshalJSONArray implements gojay's UnmarshalerJSONArray
+func (p *pathUnmarshaler) UnmarshalJSONArray(dec *gojay.Decoder) error {
+	if child := p.path.Item(dec.Index()); child != nil {
+		return p.decode(dec, child)
+	}
+	return dec.Object(&skipUnmarshaler{})
+}
+
+func (p *pathUnmarshaler) decode(dec *gojay.Decoder, child *Path) error {
+	if !child.IsProjected() {
+		return dec.Object(&pathUnmarshaler{values: p.values, fields: p.fields, path: child})
+	}
+	field := &p.fields[child.Pos]
+	if err := dec.Object(&fieldUnmarshaler{values: p.values, field: field}); err != nil {
+		return err
+	}
+	if child.IsNested() {
+		extractPath(p.values, child, p.values[child.Pos])
+	}
+	return nil
+}
+
+//extractPath assigns nested path values from already decoded value
+func extractPath(values []driver.Value, path *Path, value interface{}) {
+	switch actual := value.(type) {
+	case map[string]interface{}:
+		for key, child := range path.Keys {
+			assignPath(values, child, actual[key])
+		}
+	case []interface{}:
+		for i, child := range path.Items {
+			if index := path.indexes[i]; index < len(actual) {
+				assignPath(values, child, actual[index])
+			}
+		}
+	}
+}
+
+func assignPath(values []driver.Value, path *Path, value interface{}) {
+	if path.IsProjected() {
+		values[path.Pos] = value
+	}
+	if path.IsNested() {
+		extractPath(values, path, value)
+	}
+}
+
+// NKeys returns the number of keys to unmarshal
+func (s *skipUnmarshaler) NKeys() int { return 0 }
+
+// UnmarshalJSONObject implements gojay's UnmarshalerJSONObject
+func (s *skipUnmarshaler) UnmarshalJSONObject(dec *gojay.Decoder, k string) error {
+	return nil
+}
@@ -0,0 +1,210 @@
+package exec
+
+import (
+	"fmt"
+	"github.com/viant/parsly"
+	"github.com/viant/parsly/matcher"
+	"github.com/viant/parsly/matcher/option"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	smatcher "github.com/viant/sqlparser/matcher"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/sqlparser/query"
+	"strings"
+)
+
+const (
+	updateWhitespaceCode = iota
+	updateKeywordCode
+	updateClauseCode
+	updateWhereCode
+	updateNextCode
+	updateAssignCode
+	updateSelectorCode
+)
+
+var (
+	updateWhitespaceMatcher = parsly.NewToken(updateWhitespaceCode, "whitespace", matcher.NewWhiteSpace())
+	updateKeywordMatcher    = parsly.NewToken(updateKeywordCode, "UPDATE", matcher.NewFragment("update", &option.Case{}))
+	updateClauseMatcher     = parsly.NewToken(updateClauseCode, "SET|REMOVE|ADD|DELETE", matcher.NewSet([]string{"set", "remove", "add", "delete"}, &option.Case{}))
+	updateWhereMatcher      = parsly.NewToken(updateWhereCode, "WHERE", matcher.NewFragment("where", &option.Case{}))
+	updateNextMatcher       = parsly.NewToken(updateNextCode, ",", matcher.NewByte(','))
+	updateAssignMatcher     = parsly.NewToken(updateAssignCode, "=", matcher.NewByte('='))
+	updateSelectorMatcher   = parsly.NewToken(updateSelectorCode, "SELECTOR", smatcher.NewSelector())
+)
+
+const (
+	//AssignmentSet SET path = value
+	AssignmentSet = "SET"
+	//AssignmentAdd ADD path value, adds elements to a set
+	AssignmentAdd = "ADD"
+	//AssignmentDelete DELETE path value, removes elements from a set
+	AssignmentDelete = "DELETE"
+)
+
+type (
+	//Update represents update statement
+	Update struct {
+		Table     string
+		Set       []*Assignment
+		Remove    []string
+		Query     *query.Select
+		Returning *Returning
+		Version   string
+	}
+
+	//Assignment represents update assignment
+	Assignment struct {
+		Kind string
+		Path string
+		Expr node.Node
+	}
+)
+
+//ParseUpdate parses UPDATE table SET path = expr[, ...] REMOVE path[, ...] ADD path value DELETE path value WHERE criteria [RETURNING ...],
+//it follows sqlparser.ParseUpdate grammar, extended with DynamoDB REMOVE, ADD and DELETE clauses; values and criteria are parsed with sqlparser.ParseQualify
+//since sqlparser.ParseUpdate only accepts single operand SET values (no Views = Views + ?)
+func ParseUpdate(SQL string) (*Update, error) {
+	SQL, returning, err := ParseReturning(SQL)
+	if err != nil {
+		return nil, err
+	}
+	result := &Update{Returning: returning}
+	cursor := parsly.NewCursor("", []byte(SQL), 0)
+	if match := cursor.MatchAfterOptional(updateWhitespaceMatcher, updateKeywordMatcher); match.Code != updateKeywordCode {
+		return nil, fmt.Errorf("%w: failed to parse: %v", ErrUnsupported, cursor.NewError(updateKeywordMatcher))
+	}
+	match := cursor.MatchAfterOptional(updateWhitespaceMatcher, updateSelectorMatcher)
+	if match.Code != updateSelectorCode {
+		return nil, fmt.Errorf("%w: failed to parse: %v", ErrUnsupported, cursor.NewError(updateSelectorMatcher))
+	}
+	target := expr.NewSelector(match.Text(cursor))
+	result.Table = sqlparser.Stringify(target)
+	if err = result.parseClauses(cursor); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse: %v", ErrUnsupported, err)
+	}
+	if len(result.Set) == 0 && len(result.Remove) == 0 {
+		return nil, fmt.Errorf("%w: update statement, SET or REMOVE clause is required: %v", ErrUnsupported, SQL)
+	}
+	match = cursor.MatchAfterOptional(updateWhitespaceMatcher, updateWhereMatcher)
+	switch match.Code {
+	case updateWhereCode:
+		qualify := &expr.Qualify{}
+		if err = sqlparser.ParseQualify(cursor, qualify); err != nil {
+			return nil, fmt.Errorf("%w: failed to parse: %v", ErrUnsupported, err)
+		}
+		result.Query = &query.Select{Qualify: qualify}
+		result.Query.From.X = target
+	case parsly.EOF:
+		return result, nil
+	}
+	if err = expectEnd(cursor, updateClauseMatcher, updateWhereMatcher); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse: %v", ErrUnsupported, err)
+	}
+	return result, nil
+}
+
+//parseAssignments parses SET path = expr[, ...] items
+func (u *Update) parseAssignments(text string) error {
+	cursor := parsly.NewCursor("", []byte(text), 0)
+	if err := u.parseItems(cursor, AssignmentSet); err != nil {
+		return fmt.Errorf("%w: SET assignment: %v, %v", ErrUnsupported, text, err)
+	}
+	if err := expectEnd(cursor, updateNextMatcher); err != nil {
+		return fmt.Errorf("%w: SET assignment: %v, %v", ErrUnsupported, text, err)
+	}
+	return nil
+}
+
+//parseClauses parses SET, REMOVE, ADD and DELETE clauses
+func (u *Update) parseClauses(cursor *parsly.Cursor) error {
+	for {
+		pos := cursor.Pos
+		match := cursor.MatchAfterOptional(updateWhitespaceMatcher, updateClauseMatcher)
+		if match.Code != updateClauseCode {
+			cursor.Pos = pos
+			return nil
+		}
+		if err := u.parseItems(cursor, strings.ToUpper(match.Text(cursor))); err != nil {
+			return err
+		}
+	}
+}
+
+//parseItems parses comma separated clause items
+func (u *Update) parseItems(cursor *parsly.Cursor, kind string) error {
+	for {
+		if err := u.parseItem(cursor, kind); err != nil {
+			return err
+		}
+		pos := cursor.Pos
+		if match := cursor.MatchAfterOptional(updateWhitespaceMatcher, updateNextMatcher); match.Code != updateNextCode {
+			cursor.Pos = pos
+			return nil
+		}
+	}
+}
+
+//parseItem parses SET path = expr, REMOVE path, ADD path value or DELETE path value item
+func (u *Update) parseItem(cursor *parsly.Cursor, kind string) error {
+	match := cursor.MatchAfterOptional(updateWhitespaceMatcher, updateSelectorMatcher)
+	if match.Code != updateSelectorCode {
+		return cursor.NewError(updateSelectorMatcher)
+	}
+	path := unescapeIndexes(match.Text(cursor))
+	if kind == "REMOVE" {
+		u.Remove = append(u.Remove, path)
+		return nil
+	}
+	pos := cursor.Pos
+	if match = cursor.MatchAfterOptional(updateWhitespaceMatcher, updateAssignMatcher); match.Code != updateAssignCode {
+		if kind == AssignmentSet {
+			return cursor.NewError(updateAssignMatcher)
+		}
+		cursor.Pos = pos
+	}
+	qualify := &expr.Qualify{}
+	if err := sqlparser.ParseQualify(cursor, qualify); err != nil {
+		return err
+	}
+	value := qualify.X
+	if binary, ok := value.(*expr.Binary); ok && binary.Op == "" {
+		value = binary.X
+	}
+	if value == nil {
+		return fmt.Errorf("%v assignment: missing %v value", kind, path)
+	}
+	u.Set = append(u.Set, &Assignment{Kind: kind, Path: path, Expr: value})
+	return nil
+}
+
+//expectEnd returns an error if cursor has more than trailing whitespace
+func expectEnd(cursor *parsly.Cursor, expected ...*parsly.Token) error {
+	cursor.MatchOne(updateWhitespaceMatcher)
+	if cursor.HasMore() {
+		return cursor.NewError(expected...)
+	}
+	return nil
+}
+
+//placeholders calls fn for each placeholder in textual order
+func placeholders(n node.Node, fn func(placeholder *expr.Placeholder)) {
+	switch actual := n.(type) {
+	case *expr.Placeholder:
+		fn(actual)
+	case *expr.Binary:
+		placeholders(actual.X, fn)
+		placeholders(actual.Y, fn)
+	case *expr.Unary:
+		placeholders(actual.X, fn)
+	case *expr.Parenthesis:
+		placeholders(actual.X, fn)
+	case *expr.Call:
+		for _, arg := range actual.Args {
+			placeholders(arg, fn)
+		}
+	case *expr.Range:
+		placeholders(actual.Min, fn)
+		placeholders(actual.Max, fn)
+	}
+}
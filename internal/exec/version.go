@@ -0,0 +1,81 @@
+package exec
+
+import (
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/insert"
+	"github.com/viant/sqlparser/node"
+	"strings"
+)
+
+//initVersion adds expected version criteria (version = ?) unless criteria already references version attribute
+func (e *Execution) initVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+	e.Version = version
+	if references(e.query.Qualify.X, version) {
+		return nil
+	}
+	criteria := "(" + EscapeIndexes(stringify(e.query.Qualify.X)) + ") AND " + version + " = ?"
+	aQuery, err := sqlparser.ParseQuery("SELECT * FROM " + e.Table + " WHERE " + criteria)
+	if err != nil {
+		return err
+	}
+	aQuery.From.Alias = e.query.From.Alias
+	e.query = aQuery
+	return nil
+}
+
+//versionedInsert returns insert statement with version = 1 value unless statement already inserts version attribute
+func versionedInsert(stmt *insert.Statement, version string) *insert.Statement {
+	if version == "" {
+		return stmt
+	}
+	for _, column := range stmt.Columns {
+		if strings.EqualFold(column, version) {
+			return stmt
+		}
+	}
+	result := *stmt
+	result.Columns = append(append([]string{}, stmt.Columns...), version)
+	result.Values = append(append([]*insert.Value{}, stmt.Values...), &insert.Value{Expr: expr.NewIntLiteral("1")})
+	return &result
+}
+
+//versionIncrement returns SET version = version + 1 assignment unless update already assigns version attribute
+func (u *Update) versionIncrement() (*Assignment, error) {
+	for _, assignment := range u.Set {
+		if assignment.Path == u.Version {
+			return nil, nil
+		}
+	}
+	list, err := sqlparser.ParseList(u.Version + " + 1")
+	if err != nil {
+		return nil, err
+	}
+	return &Assignment{Kind: AssignmentSet, Path: u.Version, Expr: list[0].Expr}, nil
+}
+
+//references returns true if node references supplied attribute
+func references(n node.Node, name string) bool {
+	switch actual := n.(type) {
+	case *expr.Ident, *expr.Selector:
+		return strings.EqualFold(stringify(actual), name)
+	case *expr.Binary:
+		return references(actual.X, name) || references(actual.Y, name)
+	case *expr.Unary:
+		return references(actual.X, name)
+	case *expr.Parenthesis:
+		return references(actual.X, name)
+	case *expr.Call:
+		for _, arg := range actual.Args {
+			if references(arg, name) {
+				return true
+			}
+		}
+	case *expr.Range:
+		return references(actual.Min, name) || references(actual.Max, name)
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	//virtualQuery represents SELECT against client side virtual table, rows are filtered, sorted and projected client side
+	virtualQuery struct {
+		name    string
+		table   string
+		schema  string
+		source  []MetaColumn
+		columns []virtualColumn
+		filter  condition
+		orderBy []virtualOrder
+		limit   int
+		//checkpoint represents STREAM literal checkpoint
+		checkpoint string
+	}
+
+	virtualColumn struct {
+		name   string
+		source string
+	}
+
+	virtualOrder struct {
+		column string
+		desc   bool
+	}
+)
+
+//newVirtualExecution creates virtual table execution, params is the number of placeholders preceding WHERE clause
+func newVirtualExecution(kind Kind, table string, aQuery *virtualQuery, clauses []*Clause, params int) (*Execution, error) {
+	if err := aQuery.initColumns(clauses[0].Text); err != nil {
+		return nil, err
+	}
+	result := &Execution{Kind: kind, Table: table, Type: NewMetaType(aQuery.metaColumns()...), virtual: aQuery}
+	for i := 0; i < params; i++ {
+		result.Type.AddCriteria(NewPlaceholder(strconv.Itoa(i + 1)))
+	}
+	for _, clause := range clauses[2:] {
+		var err error
+		switch clause.Keyword {
+		case "WHERE":
+			var count int
+			if aQuery.filter, count, err = parseCondition(clause.Text, params, aQuery.sourceColumn); err == nil {
+				for i := params; i < params+count; i++ {
+					result.Type.AddCriteria(NewPlaceholder(strconv.Itoa(i + 1)))
+				}
+			}
+		case "ORDER BY":
+			err = aQuery.initOrderBy(clause.Text)
+		case "LIMIT":
+			if aQuery.limit, err = strconv.Atoi(clause.Text); err != nil {
+				err = fmt.Errorf("%w: LIMIT %v", ErrUnsupported, clause.Text)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.initState()
+	return result, nil
+}
+
+//sourceColumn returns virtual table column name for case insensitive, optionally qualified identifier
+func (q *virtualQuery) sourceColumn(name string) (string, error) {
+	name = strings.Trim(name, "\"`")
+	if index := strings.LastIndexByte(name, '.'); index != -1 {
+		name = name[index+1:]
+	}
+	for _, column := range q.source {
+		if strings.EqualFold(column.Name, name) {
+			return column.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown column %v in %v", ErrInvalid, name, q.name)
+}
+
+//initColumns initialises projection parsed by sqlparser, only stars and columns with optional alias are supported
+func (q *virtualQuery) initColumns(list string) error {
+	items, err := sqlparser.ParseList(list)
+	if err != nil {
+		return fmt.Errorf("%w: %v projection: %v, %v", ErrUnsupported, q.name, list, err)
+	}
+	for _, item := range items {
+		switch item.Expr.(type) {
+		case *expr.Star:
+			for _, column := range q.source {
+				q.columns = append(q.columns, virtualColumn{name: column.Name, source: column.Name})
+			}
+			continue
+		case *expr.Ident, *expr.Selector:
+		default:
+			return fmt.Errorf("%w: %v projection: %v", ErrUnsupported, q.name, stringify(item.Expr))
+		}
+		name := stringify(item.Expr)
+		source, err := q.sourceColumn(name)
+		if err != nil {
+			return err
+		}
+		if index := strings.LastIndexByte(name, '.'); index != -1 {
+			name = name[index+1:]
+		}
+		if item.Alias != "" {
+			name = item.Alias
+		}
+		q.columns = append(q.columns, virtualColumn{name: name, source: source})
+	}
+	return nil
+}
+
+//initOrderBy initialises ORDER BY parsed by sqlparser, only projected or source columns are supported
+func (q *virtualQuery) initOrderBy(list string) error {
+	aQuery, err := sqlparser.ParseQuery("SELECT * FROM t ORDER BY " + list)
+	if err != nil {
+		return fmt.Errorf("%w: ORDER BY %v: %v", ErrUnsupported, list, err)
+	}
+	if len(aQuery.OrderBy) == 0 {
+		return fmt.Errorf("%w: ORDER BY %v", ErrUnsupported, list)
+	}
+	for _, item := range aQuery.OrderBy {
+		switch item.Expr.(type) {
+		case *expr.Ident, *expr.Selector:
+		default:
+			return fmt.Errorf("%w: ORDER BY %v", ErrUnsupported, stringify(item.Expr))
+		}
+		name := stringify(item.Expr)
+		order := virtualOrder{desc: strings.EqualFold(item.Direction, "DESC")}
+		for _, column := range q.columns {
+			if strings.EqualFold(column.name, name) {
+				order.column = column.source
+			}
+		}
+		if order.column == "" {
+			column, err := q.sourceColumn(name)
+			if err != nil {
+				return err
+			}
+			order.column = column
+		}
+		q.orderBy = append(q.orderBy, order)
+	}
+	return nil
+}
+
+func (q *virtualQuery) metaColumns() []MetaColumn {
+	var result []MetaColumn
+	for _, column := range q.columns {
+		for _, source := range q.source {
+			if source.Name == column.source {
+				result = append(result, MetaColumn{Name: column.name, Type: source.Type})
+			}
+		}
+	}
+	return result
+}
+
+//items returns filtered, sorted, limited and projected virtual table rows
+func (q *virtualQuery) items(rows []map[string]interface{}, args []driver.NamedValue) ([]map[string]types.AttributeValue, error) {
+	var matched []map[string]interface{}
+	for _, row := range rows {
+		if q.filter != nil {
+			ok, err := q.filter.eval(row, args)
+			if err != nil {
+				return nil, err
+			}
+			if !isTrue(ok) {
+				continue
+			}
+		}
+		matched = append(matched, row)
+	}
+	if len(q.orderBy) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			for _, order := range q.orderBy {
+				result, _ := compare(matched[i][order.column], matched[j][order.column])
+				if result == 0 {
+					continue
+				}
+				return (result < 0) != order.desc
+			}
+			return false
+		})
+	}
+	if q.limit >= 0 && len(matched) > q.limit {
+		matched = matched[:q.limit]
+	}
+	var result []map[string]types.AttributeValue
+	for _, row := range matched {
+		item := make(map[string]types.AttributeValue, len(q.columns))
+		for _, column := range q.columns {
+			if value, ok := row[column.source].(types.AttributeValue); ok {
+				item[column.name] = value
+				continue
+			}
+			value, err := Encode(row[column.source])
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %v: %w", column.name, err)
+			}
+			item[column.name] = value
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/viant/dyndb/internal/exec"
+)
+
+//metaItems returns SHOW TABLES, DESCRIBE, SHOW INDEXES, information_schema or EXPLAIN rows
+func (s *Statement) metaItems(ctx context.Context, args []driver.NamedValue) ([]map[string]types.AttributeValue, error) {
+	switch s.execution.Kind {
+	case exec.KindSchema:
+		tables, err := s.execution.SchemaTables(args)
+		if err != nil {
+			return nil, err
+		}
+		if tables == nil {
+			if tables, err = listTables(ctx, s.client); err != nil {
+				return nil, err
+			}
+		}
+		var descs []*types.TableDescription
+		for _, table := range tables {
+			desc, err := s.schemas.optionalDescribe(ctx, s.client, table)
+			if err != nil {
+				return nil, err
+			}
+			if desc != nil {
+				descs = append(descs, desc)
+			}
+		}
+		return s.execution.SchemaItems(descs, args)
+	case exec.KindShowTables:
+		tables, err := listTables(ctx, s.client)
+		if err != nil {
+			return nil, err
+		}
+		return exec.TableItems(tables)
+	case exec.KindDescribe, exec.KindShowIndexes:
+		desc, err := s.schemas.describe(ctx, s.client, s.execution.Table)
+		if err != nil {
+			return nil, err
+		}
+		if s.execution.Kind == exec.KindDescribe {
+			return exec.DescribeItems(desc)
+		}
+		return exec.IndexItems(desc)
+	}
+	return s.execution.Items, nil
+}
+
+//listTables returns all table names
+func listTables(ctx context.Context, client *dynamodb.Client) ([]string, error) {
+	var result []string
+	paginator := dynamodb.NewListTablesPaginator(client, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, output.TableNames...)
+	}
+	return result, nil
+}
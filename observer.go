@@ -0,0 +1,58 @@
+package dyndb
+
+import (
+	"context"
+	"time"
+)
+
+//Event represents statement execution or query page fetch
+type Event struct {
+	//SQL is the original SQL
+	SQL string
+	//PartiQL is the statement sent to DynamoDB
+	PartiQL string
+	//Parameters is the number of bound parameters
+	Parameters int
+	//Page is the fetched query page number starting from 1, 0 for statement execution
+	Page int
+	//Duration is the statement execution or page fetch time
+	Duration time.Duration
+	//Items is the number of fetched items or affected rows
+	Items int
+	//ConsumedCapacity is the capacity consumed by statement execution or page fetch
+	ConsumedCapacity *ConsumedCapacity
+	//Err is the execution error
+	Err error
+	//Slow is true when duration reached connector slow threshold
+	Slow bool
+}
+
+//Observer observes statement executions and query page fetches
+type Observer interface {
+	Observe(ctx context.Context, event *Event)
+}
+
+//ObserverFunc represents observer function
+type ObserverFunc func(ctx context.Context, event *Event)
+
+//Observe calls observer function
+func (f ObserverFunc) Observe(ctx context.Context, event *Event) {
+	f(ctx, event)
+}
+
+type observer struct {
+	observers     []Observer
+	slowThreshold time.Duration
+}
+
+//observe notifies observers, started is the execution start time
+func (o *observer) observe(ctx context.Context, started time.Time, event *Event) {
+	if o == nil || len(o.observers) == 0 {
+		return
+	}
+	event.Duration = time.Since(started)
+	event.Slow = o.slowThreshold > 0 && event.Duration >= o.slowThreshold
+	for _, item := range o.observers {
+		item.Observe(ctx, event)
+	}
+}
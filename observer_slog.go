@@ -0,0 +1,53 @@
+package dyndb
+
+import (
+	"context"
+	"log/slog"
+)
+
+//SlogObserver logs statement executions and query page fetches with structured logger
+type SlogObserver struct {
+	Logger *slog.Logger
+	//Level is used for regular events, slow events are logged with warning, failures with error level
+	Level slog.Level
+}
+
+//Observe logs event
+func (o *SlogObserver) Observe(ctx context.Context, event *Event) {
+	level, message := o.Level, "dyndb statement"
+	switch {
+	case event.Err != nil:
+		level, message = slog.LevelError, "dyndb statement failed"
+	case event.Slow:
+		level, message = slog.LevelWarn, "dyndb slow statement"
+	}
+	if !o.Logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("sql", event.SQL),
+		slog.String("partiql", event.PartiQL),
+		slog.Int("parameters", event.Parameters),
+		slog.Int("page", event.Page),
+		slog.Duration("duration", event.Duration),
+		slog.Int("items", event.Items),
+	}
+	if capacity := event.ConsumedCapacity; capacity != nil {
+		attrs = append(attrs, slog.Group("capacity",
+			slog.Float64("units", capacity.CapacityUnits),
+			slog.Float64("read", capacity.ReadCapacityUnits),
+			slog.Float64("write", capacity.WriteCapacityUnits)))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.Any("error", event.Err))
+	}
+	o.Logger.LogAttrs(ctx, level, message, attrs...)
+}
+
+//NewSlogObserver creates structured log observer, regular events are logged with debug level
+func NewSlogObserver(logger *slog.Logger) *SlogObserver {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogObserver{Logger: logger, Level: slog.LevelDebug}
+}
@@ -0,0 +1,49 @@
+package dyndb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlogObserver_Observe(t *testing.T) {
+	var testCases = []struct {
+		description string
+		threshold   time.Duration
+		event       *Event
+		expect      []string
+	}{
+		{
+			description: "regular statement",
+			event:       &Event{SQL: "SELECT * FROM Users", PartiQL: "SELECT * FROM Users", Page: 1, Items: 3},
+			expect:      []string{`level=DEBUG msg="dyndb statement"`, `sql="SELECT * FROM Users"`, "page=1", "items=3"},
+		},
+		{
+			description: "slow statement",
+			threshold:   time.Nanosecond,
+			event:       &Event{SQL: "UPDATE Users SET Name = ?", Parameters: 1, ConsumedCapacity: &ConsumedCapacity{Capacity: Capacity{CapacityUnits: 1, WriteCapacityUnits: 1}}},
+			expect:      []string{`level=WARN msg="dyndb slow statement"`, "parameters=1", "capacity.units=1", "capacity.write=1"},
+		},
+		{
+			description: "failed statement",
+			event:       &Event{SQL: "DELETE FROM Users", Err: errors.New("test error")},
+			expect:      []string{`level=ERROR msg="dyndb statement failed"`, `error="test error"`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		buffer := &bytes.Buffer{}
+		logger := slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		target := &observer{observers: []Observer{NewSlogObserver(logger)}, slowThreshold: testCase.threshold}
+		target.observe(context.Background(), time.Now().Add(-time.Millisecond), testCase.event)
+		actual := buffer.String()
+		for _, expect := range testCase.expect {
+			assert.Truef(t, strings.Contains(actual, expect), "%v: expected %v in %v", testCase.description, expect, actual)
+		}
+	}
+}
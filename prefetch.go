@@ -0,0 +1,163 @@
+package dyndb
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+	ndynamodb "github.com/viant/dyndb/internal/dynamodb"
+	"github.com/viant/dyndb/internal/exec"
+	"time"
+)
+
+type (
+	//prefetcher fetches next pages in background, pages channel bounds number of buffered pages
+	prefetcher struct {
+		ctx    context.Context
+		cancel context.CancelFunc
+		pages  chan *prefetchedPage
+		err    error //set when prefetch was cancelled before reading all pages
+	}
+
+	prefetchedPage struct {
+		ql         string
+		parameters []types.AttributeValue
+		token      *string
+		request    int
+		page       *ndynamodb.Page
+		elapsed    time.Duration
+		err        error
+	}
+)
+
+//startPrefetch starts fetching next pages in background when context requests prefetch
+func (r *Rows) startPrefetch(ctx context.Context) {
+	pages, _ := ctx.Value(prefetchKey).(int)
+	input, request := r.nextInput()
+	if pages <= 0 || (input.NextToken == nil && len(r.pending) == 0) {
+		return
+	}
+	prefetchCtx, cancel := context.WithCancel(ctx)
+	r.prefetch = &prefetcher{ctx: prefetchCtx, cancel: cancel, pages: make(chan *prefetchedPage, pages)}
+	go r.prefetch.run(r.client, input, r.pending, request)
+	r.pending = nil
+}
+
+//nextInput returns input of the page following the current or the last sampled page with its request index
+func (r *Rows) nextInput() (*dynamodb.ExecuteStatementInput, int) {
+	input := &dynamodb.ExecuteStatementInput{
+		Statement:              aws.String(r.ql),
+		NextToken:              r.nextToken,
+		Parameters:             r.parameters,
+		Limit:                  r.limit,
+		ConsistentRead:         r.consistentRead,
+		ReturnConsumedCapacity: types.ReturnConsumedCapacityIndexes,
+	}
+	request := r.request
+	if count := len(r.sampled); count > 0 {
+		last := r.sampled[count-1]
+		input.Statement, input.Parameters, input.NextToken = aws.String(last.ql), last.parameters, last.page.NextToken
+		request = last.request
+	}
+	return input, request
+}
+
+//sampleWildcard fetches up to pages-1 pages following the first one (all pages for 0) to discover SELECT * attributes before columns are fixed,
+//sampled pages are read before fetching the following ones
+func (r *Rows) sampleWildcard(ctx context.Context, pages int) error {
+	output := ndynamodb.NewExecuteStatementOutput(r.state.Type)
+	for sampled := 1; pages == 0 || sampled < pages; sampled++ {
+		input, request := r.nextInput()
+		if input.NextToken == nil {
+			if len(r.pending) == 0 {
+				return nil
+			}
+			input.Statement, input.Parameters = aws.String(r.pending[0].Query), r.pending[0].Parameters
+			r.pending = r.pending[1:]
+			request++
+		}
+		item := fetchPage(ctx, r.client, input)
+		item.request = request
+		if item.err != nil {
+			return newError(item.err, r.execution.SQL, item.ql)
+		}
+		if err := output.LoadPage(item.page); err != nil {
+			return err
+		}
+		r.sampled = append(r.sampled, item)
+	}
+	return nil
+}
+
+func (p *prefetcher) run(client *dynamodb.Client, input *dynamodb.ExecuteStatementInput, pending []*exec.Request, request int) {
+	defer close(p.pages)
+	for {
+		if input.NextToken == nil {
+			if len(pending) == 0 {
+				return
+			}
+			input.Statement, input.Parameters = aws.String(pending[0].Query), pending[0].Parameters
+			pending = pending[1:]
+			request++
+		}
+		item := fetchPage(p.ctx, client, input)
+		item.request = request
+		select {
+		case p.pages <- item:
+		case <-p.ctx.Done():
+			p.err = p.ctx.Err()
+			return
+		}
+		if item.err != nil {
+			return
+		}
+		input.NextToken = item.page.NextToken
+	}
+}
+
+//fetchPage fetches raw page with PageMiddleware
+func fetchPage(ctx context.Context, client *dynamodb.Client, input *dynamodb.ExecuteStatementInput) *prefetchedPage {
+	started := time.Now()
+	deserializer := &ndynamodb.PageMiddleware{}
+	_, err := client.ExecuteStatement(ctx, input, func(options *dynamodb.Options) {
+		options.APIOptions = append(options.APIOptions, func(stack *middleware.Stack) error {
+			stack.Deserialize.Clear()
+			return stack.Deserialize.Add(deserializer, middleware.After)
+		})
+	})
+	return &prefetchedPage{ql: *input.Statement, parameters: input.Parameters, token: input.NextToken, page: deserializer.Page, elapsed: time.Since(started), err: err}
+}
+
+//close stops background fetching
+func (p *prefetcher) close() {
+	p.cancel()
+}
+
+//nextPrefetched loads next prefetched page, it returns false when there are no more pages
+func (r *Rows) nextPrefetched() (bool, error) {
+	item, ok := <-r.prefetch.pages
+	if !ok {
+		return false, r.prefetch.err
+	}
+	err := r.loadPage(item)
+	return err == nil, err
+}
+
+//loadPage loads prefetched or sampled page and notifies observers
+func (r *Rows) loadPage(item *prefetchedPage) error {
+	r.ql, r.parameters, r.pageToken, r.request = item.ql, item.parameters, item.token, item.request
+	err := item.err
+	if err == nil {
+		if err = r.deserializer.Output.LoadPage(item.page); err == nil {
+			r.nextToken = item.page.NextToken
+			r.index = 0
+			r.addCapacity(item.page.ConsumedCapacity)
+		}
+	}
+	if err != nil {
+		err = newError(err, r.execution.SQL, r.ql)
+	}
+	r.observePage(time.Now().Add(-item.elapsed), err)
+	return err
+}
@@ -0,0 +1,46 @@
+package dyndb
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRows_Prefetch(t *testing.T) {
+	server := newTestServer(publicationPages())
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var testCases = []struct {
+		description string
+		pages       int
+	}{
+		{description: "without prefetch"},
+		{description: "single page prefetch", pages: 1},
+		{description: "multi page prefetch", pages: 3},
+	}
+
+	for _, testCase := range testCases {
+		consumed := NewConsumedCapacity()
+		ctx := WithConsumedCapacity(WithPrefetch(context.Background(), testCase.pages), consumed)
+		rows, err := db.QueryContext(ctx, "SELECT ISBN, Price FROM Publication")
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []string
+		for rows.Next() {
+			var ISBN string
+			var price int
+			if assert.Nil(t, rows.Scan(&ISBN, &price), testCase.description) {
+				actual = append(actual, ISBN)
+			}
+		}
+		assert.Nil(t, rows.Err(), testCase.description)
+		assert.Nil(t, rows.Close(), testCase.description)
+		assert.EqualValues(t, []string{"0-1", "0-2", "1-1", "1-2", "2-1", "2-2"}, actual, testCase.description)
+		assert.EqualValues(t, 1.5, consumed.ReadCapacityUnits, testCase.description)
+	}
+}
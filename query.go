@@ -0,0 +1,29 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql"
+	"io"
+)
+
+//Query runs SQL and returns rows decoded into T structs with Rows.ScanStruct, args support sql.Named
+func Query[T any](ctx context.Context, db *sql.DB, SQL string, args ...interface{}) ([]T, error) {
+	var result []T
+	err := queryRows(ctx, db, SQL, args, func(rows *Rows) error {
+		var item T
+		for {
+			result = append(result, item)
+			if err := rows.ScanStruct(&result[len(result)-1]); err != nil {
+				result = result[:len(result)-1]
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -8,6 +8,7 @@ var errLastInsertID = errors.New("lastInsertId is not supported")
 
 type result struct {
 	totalRows int64
+	consumed  *ConsumedCapacity
 }
 
 //LastInsertId returns not supported error
@@ -19,3 +20,8 @@ func (r *result) LastInsertId() (int64, error) {
 func (r *result) RowsAffected() (int64, error) {
 	return r.totalRows, nil
 }
+
+//ConsumedCapacity returns capacity consumed by the statement
+func (r *result) ConsumedCapacity() *ConsumedCapacity {
+	return r.consumed
+}
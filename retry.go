@@ -0,0 +1,47 @@
+package dyndb
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+//retryableErrorCodes error codes retried in addition to SDK throttling, timeout, 5xx and connection errors
+var retryableErrorCodes = map[string]struct{}{
+	"TransactionConflictException": {},
+	"InternalServerError":          {},
+	"ServiceUnavailable":           {},
+}
+
+//newRetryer returns exponential backoff with jitter retryer used for all connector connections requests including query pages and stream records
+func newRetryer(cfg *Config) aws.Retryer {
+	return retry.NewStandard(func(options *retry.StandardOptions) {
+		if cfg.MaxAttempts > 0 {
+			options.MaxAttempts = cfg.MaxAttempts
+		}
+		if cfg.MaxBackoff > 0 {
+			options.MaxBackoff = cfg.MaxBackoff
+		}
+		switch {
+		case cfg.RetryBudget == 0:
+			options.RateLimiter = unlimitedBudget{}
+		case cfg.RetryBudget > 0:
+			options.RateLimiter = ratelimit.NewTokenRateLimit(uint(cfg.RetryBudget))
+		}
+		options.Retryables = append(options.Retryables, retry.RetryableErrorCode{Codes: retryableErrorCodes})
+	})
+}
+
+//unlimitedBudget represents retry rate limiter without budget
+type unlimitedBudget struct{}
+
+//GetToken returns retry token
+func (unlimitedBudget) GetToken(ctx context.Context, cost uint) (func() error, error) {
+	return func() error { return nil }, nil
+}
+
+//AddTokens adds tokens
+func (unlimitedBudget) AddTokens(uint) error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewRetryer(t *testing.T) {
+	cfg, err := ParseDSN("dynamodb://localhost:8000/us-west-1?maxAttempts=5&maxBackoff=2s&retryBudget=10")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 5, cfg.MaxAttempts)
+	assert.EqualValues(t, 2*time.Second, cfg.MaxBackoff)
+	retryer := newRetryer(cfg)
+	assert.EqualValues(t, 5, retryer.MaxAttempts())
+
+	for _, option := range []string{"maxAttempts=five", "maxAttempts=0", "maxAttempts=-1", "retryBudget=10x", "retryBudget=-2", "maxBackoff=2"} {
+		_, err := ParseDSN("dynamodb://localhost:8000/us-west-1?" + option)
+		assert.NotNil(t, err, option)
+	}
+
+	var testCases = []struct {
+		description string
+		err         error
+		expect      bool
+	}{
+		{description: "throughput exceeded", err: &types.ProvisionedThroughputExceededException{}, expect: true},
+		{description: "request limit", err: &types.RequestLimitExceeded{}, expect: true},
+		{description: "internal server error", err: &types.InternalServerError{}, expect: true},
+		{description: "transaction conflict", err: &types.TransactionConflictException{}, expect: true},
+		{description: "condition failed", err: &types.ConditionalCheckFailedException{}, expect: false},
+	}
+	for _, testCase := range testCases {
+		assert.EqualValues(t, testCase.expect, retryer.IsErrorRetryable(testCase.err), testCase.description)
+	}
+
+	throttled := &types.ProvisionedThroughputExceededException{}
+	_, err = retryer.GetRetryToken(context.Background(), throttled)
+	assert.Nil(t, err, "budget available")
+	_, err = retryer.GetRetryToken(context.Background(), throttled)
+	assert.Nil(t, err, "budget available")
+	_, err = retryer.GetRetryToken(context.Background(), throttled)
+	assert.NotNil(t, err, "budget exhausted")
+}
+
+func TestConnector_RetryBudget(t *testing.T) {
+	server := newTestServer(map[string]testHandler{
+		"DescribeTable": staticOutput(publicationTable),
+		"ExecuteStatement": func(input []byte) (string, error) {
+			return "", testError("ProvisionedThroughputExceededException")
+		},
+	})
+	defer server.Close()
+	InvalidateSchema("Publication")
+	db, err := server.Open("maxAttempts=3", "maxBackoff=1ms", "retryBudget=10")
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 2; i++ {
+		conn, err := db.Conn(ctx)
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+	var expectCalls = []int{3, 4} //the first connection retries twice exhausting the budget shared with the second one
+	for i, conn := range conns {
+		_, err = conn.QueryContext(ctx, "SELECT ISBN FROM Publication")
+		assert.NotNil(t, err)
+		assert.EqualValues(t, expectCalls[i], server.Calls("ExecuteStatement"))
+	}
+}
@@ -11,28 +11,51 @@ import (
 	"github.com/viant/dyndb/internal/exec"
 	"io"
 	"reflect"
+	"time"
 )
 
 //Rows represents rows driver
 type Rows struct {
-	execution    *exec.Execution
-	client       *dynamodb.Client
-	parameters   []types.AttributeValue
-	deserializer *ndynamodb.DeserializeMiddleware
-	columns      []string
-	state        *exec.State
-	index        int
-	nextToken    *string
-	ql           string
-	limit        *int32
+	execution      *exec.Execution
+	client         *dynamodb.Client
+	parameters     []types.AttributeValue
+	deserializer   *ndynamodb.DeserializeMiddleware
+	columns        []string
+	state          *exec.State
+	index          int //current page row index
+	read           int
+	nextToken      *string
+	pageToken      *string //token of the current page
+	request        int     //current request (IN list chunk) index
+	requests       int
+	queryHash      uint32 //hash of query requests issuing continuation token
+	continuation   *Continuation
+	ql             string
+	pending        []*exec.Request
+	limit          *int32
+	consistentRead *bool
+	consumed       *ConsumedCapacity
+	pageCapacity   *ConsumedCapacity
+	collectors     capacities
+	ctx            context.Context
+	observer       *observer
+	event          *Event
+	prefetch       *prefetcher
+	sampled        []*prefetchedPage //pages fetched to infer SELECT * columns, read before fetching next pages
+	stream         *streamReader
+	mapping        *structMapping
+	values         []driver.Value
 }
 
 func (r *Rows) executeQueryStatement(ctx context.Context) error {
+	r.pageToken = r.nextToken
 	_, err := r.client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
-		Statement:  &r.ql,
-		NextToken:  r.nextToken,
-		Parameters: r.parameters,
-		Limit:      r.limit,
+		Statement:              &r.ql,
+		NextToken:              r.nextToken,
+		Parameters:             r.parameters,
+		Limit:                  r.limit,
+		ConsistentRead:         r.consistentRead,
+		ReturnConsumedCapacity: types.ReturnConsumedCapacityIndexes,
 	}, func(options *dynamodb.Options) {
 		options.APIOptions = append(options.APIOptions, func(stack *middleware.Stack) error {
 			stack.Deserialize.Clear()
@@ -43,13 +66,25 @@ func (r *Rows) executeQueryStatement(ctx context.Context) error {
 		return err
 	}
 	r.nextToken = r.deserializer.Output.NextToken
-	if err != nil {
-		return err
-	}
+	r.index = 0
+	r.addCapacity(r.deserializer.Output.ConsumedCapacity)
 	return nil
 
 }
 
+//addCapacity sets page capacity and adds it to collectors
+func (r *Rows) addCapacity(consumed *types.ConsumedCapacity) {
+	write := r.execution.Kind != exec.KindUndefined
+	r.pageCapacity = NewConsumedCapacity()
+	r.pageCapacity.add(consumed, write)
+	r.collectors.add(consumed, write)
+}
+
+//ConsumedCapacity returns capacity consumed by all fetched pages
+func (r *Rows) ConsumedCapacity() *ConsumedCapacity {
+	return r.consumed
+}
+
 // Columns returns query columns
 func (r *Rows) Columns() []string {
 	if len(r.columns) > 0 {
@@ -68,6 +103,9 @@ func (r *Rows) Close() error {
 	if r.state == nil {
 		return nil
 	}
+	if r.prefetch != nil {
+		r.prefetch.close()
+	}
 	r.execution.ReleaseState(r.state)
 	r.state = nil
 	return nil
@@ -75,37 +113,123 @@ func (r *Rows) Close() error {
 
 // Next moves to next row
 func (r *Rows) Next(dest []driver.Value) error {
-	if !r.hasNext() {
-		if r.nextToken == nil {
-			return io.EOF
+	data, err := r.nextRow()
+	if err != nil {
+		return err
+	}
+	r.state.SetDest(dest)
+	if err = gojay.Unmarshal(data, r.state); err == nil {
+		err = r.state.Reconcile()
+	}
+	return err
+}
+
+//nextRow returns next row item data, pages are fetched as needed
+func (r *Rows) nextRow() ([]byte, error) {
+	defer r.updateContinuation()
+	for !r.hasNext() {
+		if r.isLimited() {
+			return nil, io.EOF
+		}
+		if len(r.sampled) > 0 {
+			item := r.sampled[0]
+			r.sampled = r.sampled[1:]
+			if err := r.loadPage(item); err != nil {
+				return nil, err
+			}
+			continue
 		}
-		if err := r.executeQueryStatement(context.Background()); err != nil {
-			return err
+		if r.stream != nil {
+			if err := r.nextStreamPage(); err != nil {
+				return nil, err
+			}
+			continue
 		}
-		if !r.hasNext() {
-			return io.EOF
+		if r.prefetch != nil {
+			if ok, err := r.nextPrefetched(); !ok {
+				if err == nil {
+					err = io.EOF
+				}
+				return nil, err
+			}
+			continue
+		}
+		if !r.nextRequest() {
+			return nil, io.EOF
+		}
+		if err := r.fetchPage(); err != nil {
+			return nil, err
 		}
 	}
 	output := r.deserializer.Output
-
 	row := output.Rows[r.index]
 	r.index++
-	data := output.Data[row.Begin:row.End]
+	r.read++
+	return output.Data[row.Begin:row.End], nil
+}
 
-	r.state.SetDest(dest)
-	err := gojay.Unmarshal(data, r.state)
-	if err == nil {
-		err = r.state.Reconcile()
+//nextStreamPage loads next stream records page, it returns io.EOF once stream shards are caught up
+func (r *Rows) nextStreamPage() error {
+	items, err := r.stream.next(r.ctx)
+	if err != nil {
+		return newError(err, r.execution.SQL, "")
 	}
+	if len(items) == 0 {
+		return io.EOF
+	}
+	r.index = 0
+	return r.deserializer.Output.Load(items...)
+}
+
+//fetchPage fetches next page and notifies observers
+func (r *Rows) fetchPage() error {
+	started := time.Now()
+	err := r.executeQueryStatement(r.ctx)
+	if err != nil {
+		err = newError(err, r.execution.SQL, r.ql)
+	}
+	r.observePage(started, err)
 	return err
 }
 
+//observePage notifies observers about fetched page
+func (r *Rows) observePage(started time.Time, err error) {
+	if r.event == nil {
+		return
+	}
+	r.event.Page++
+	event := *r.event
+	event.Err = err
+	if err == nil {
+		event.Items = len(r.deserializer.Output.Rows)
+		event.ConsumedCapacity = r.pageCapacity
+	}
+	r.observer.observe(r.ctx, started, &event)
+}
+
+//nextRequest returns true if there is next page to fetch, pending IN list chunk request is used when current request is exhausted
+func (r *Rows) nextRequest() bool {
+	if r.nextToken != nil {
+		return true
+	}
+	if len(r.pending) == 0 {
+		return false
+	}
+	r.ql, r.parameters = r.pending[0].Query, r.pending[0].Parameters
+	r.pending = r.pending[1:]
+	r.request++
+	return true
+}
+
+//isLimited returns true if limit rows has been read
+func (r *Rows) isLimited() bool {
+	return r.limit != nil && r.read >= int(*r.limit)
+}
+
 // hasNext returns true if there is next row to fetch.
 func (r *Rows) hasNext() bool {
-	if r.limit != nil {
-		if withinLimit := r.index < int(*r.limit); !withinLimit {
-			return withinLimit
-		}
+	if r.isLimited() {
+		return false
 	}
 	return r.index < len(r.deserializer.Output.Rows)
 }
@@ -145,5 +269,9 @@ func (r *Rows) ColumnTypeDatabaseTypeName(index int) string {
 
 // ColumnTypeNullable returns if column is nullable
 func (r *Rows) ColumnTypeNullable(index int) (nullable, ok bool) {
-	return !r.deserializer.Output.Type.Fields[index].Required, true
+	column := r.state.Type.Columns[index]
+	if len(column.Fields) == 0 {
+		return true, true
+	}
+	return !r.state.Type.Fields[column.Fields[0]].Required, true
 }
@@ -0,0 +1,170 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestRows_Next(t *testing.T) {
+	var pages []string
+	//the first page is the longest one, so that stale regions would point past the next page data
+	for page := 0; page < 3; page++ {
+		var items []string
+		for i := 0; i < 3-page; i++ {
+			items = append(items, fmt.Sprintf(`{"ISBN":{"S":"%v-%v"},"Title":{"S":"publication %v of page %v"}}`, page, i+1, i+1, page))
+		}
+		pages = append(pages, strings.Join(items, ","))
+	}
+	server := newTestServer(map[string]testHandler{"DescribeTable": staticOutput(publicationTable), "ExecuteStatement": itemPages(pages...)})
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var testCases = []struct {
+		description string
+		SQL         string
+		expect      []string
+	}{
+		{description: "all pages", SQL: "SELECT ISBN FROM Publication", expect: []string{"0-1", "0-2", "0-3", "1-1", "1-2", "2-1"}},
+		{description: "limit across pages", SQL: "SELECT ISBN FROM Publication LIMIT 4", expect: []string{"0-1", "0-2", "0-3", "1-1"}},
+	}
+
+	for _, testCase := range testCases {
+		rows, err := db.Query(testCase.SQL)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []string
+		for rows.Next() {
+			var ISBN string
+			if assert.Nil(t, rows.Scan(&ISBN), testCase.description) {
+				actual = append(actual, ISBN)
+			}
+		}
+		assert.Nil(t, rows.Err(), testCase.description)
+		assert.Nil(t, rows.Close(), testCase.description)
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
+
+func TestRows_Next_Canceled(t *testing.T) {
+	server := newTestServer(publicationPages())
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = queryRows(ctx, db, "SELECT ISBN, Price FROM Publication", nil, func(rows *Rows) error {
+		dest := make([]driver.Value, 2)
+		for i := 0; i < 2; i++ { //the first page rows
+			if err := rows.Next(dest); err != nil {
+				return err
+			}
+		}
+		cancel()
+		return rows.Next(dest)
+	})
+	assert.True(t, errors.Is(err, context.Canceled), err)
+}
+
+func TestRows_Wildcard(t *testing.T) {
+	var testCases = []struct {
+		description   string
+		options       []string
+		pages         []string
+		expectColumns []string
+		expectTypes   []string
+		expect        []string
+	}{
+		{
+			description:   "same attributes on all pages",
+			pages:         []string{`{"ISBN":{"S":"1"},"Price":{"N":"1"}}`, `{"ISBN":{"S":"2"},"Price":{"N":"2"}}`},
+			expectColumns: []string{"ISBN", "Price"},
+			expectTypes:   []string{"STRING", "DECIMAL"},
+			expect:        []string{"1:1", "2:2"},
+		},
+		{
+			description:   "decimal introduced on the second page",
+			pages:         []string{`{"ISBN":{"S":"1"},"Price":{"N":"1"}}`, `{"ISBN":{"S":"2"},"Price":{"N":"2.5"}}`},
+			expectColumns: []string{"ISBN", "Price"},
+			expectTypes:   []string{"STRING", "DECIMAL"},
+			expect:        []string{"1:1", "2:2.5"},
+		},
+		{
+			description:   "attribute introduced after the sampled page is skipped",
+			pages:         []string{`{"ISBN":{"S":"1"},"Price":{"N":"1"}}`, `{"ISBN":{"S":"2"},"Price":{"N":"2"},"Title":{"S":"Go"}}`},
+			expectColumns: []string{"ISBN", "Price"},
+			expectTypes:   []string{"STRING", "DECIMAL"},
+			expect:        []string{"1:1", "2:2"},
+		},
+		{
+			description:   "attribute introduced on the sampled second page",
+			options:       []string{"wildcardPages=2"},
+			pages:         []string{`{"ISBN":{"S":"1"},"Price":{"N":"1"}}`, `{"ISBN":{"S":"2"},"Price":{"N":"2"},"Title":{"S":"Go"}}`, `{"ISBN":{"S":"3"},"Price":{"N":"3"}}`},
+			expectColumns: []string{"ISBN", "Price", "Title"},
+			expectTypes:   []string{"STRING", "DECIMAL", "STRING"},
+			expect:        []string{"1:1:<nil>", "2:2:Go", "3:3:<nil>"},
+		},
+		{
+			description:   "all pages sampled",
+			options:       []string{"wildcardPages=0"},
+			pages:         []string{`{"ISBN":{"S":"1"}}`, `{"ISBN":{"S":"2"}}`, `{"ISBN":{"S":"3"},"Price":{"N":"3"}}`},
+			expectColumns: []string{"ISBN", "Price"},
+			expectTypes:   []string{"STRING", "DECIMAL"},
+			expect:        []string{"1:<nil>", "2:<nil>", "3:3"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		server := newTestServer(map[string]testHandler{"DescribeTable": staticOutput(publicationTable), "ExecuteStatement": itemPages(testCase.pages...)})
+		db, err := server.Open(testCase.options...)
+		if !assert.Nil(t, err, testCase.description) {
+			server.Close()
+			continue
+		}
+		rows, err := db.Query("SELECT * FROM Publication")
+		if !assert.Nil(t, err, testCase.description) {
+			server.Close()
+			continue
+		}
+		columns, _ := rows.Columns()
+		assert.EqualValues(t, testCase.expectColumns, columns, testCase.description)
+		columnTypes, _ := rows.ColumnTypes()
+		var actualTypes []string
+		for _, columnType := range columnTypes {
+			actualTypes = append(actualTypes, columnType.DatabaseTypeName())
+		}
+		assert.EqualValues(t, testCase.expectTypes, actualTypes, testCase.description)
+		var actual []string
+		for rows.Next() {
+			values := make([]interface{}, len(columns))
+			for i := range values {
+				values[i] = new(interface{})
+			}
+			if assert.Nil(t, rows.Scan(values...), testCase.description) {
+				var texts []string
+				for _, value := range values {
+					texts = append(texts, fmt.Sprintf("%v", *value.(*interface{})))
+				}
+				actual = append(actual, strings.Join(texts, ":"))
+			}
+		}
+		assert.Nil(t, rows.Err(), testCase.description)
+		_ = rows.Close()
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+		assert.EqualValues(t, len(testCase.pages), server.Calls("ExecuteStatement"), testCase.description)
+		server.Close()
+	}
+
+	_, err := ParseDSN("dynamodb://localhost:8000/us-west-1?wildcardPages=-1")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,173 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/viant/dyndb/internal/exec"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+type (
+	//structMapping maps row columns to struct fields, scalar attributes are decoded directly into fields, other columns are converted from row values
+	structMapping struct {
+		rType   reflect.Type
+		decoder *exec.StructDecoder
+		fields  []*structField
+	}
+
+	structField struct {
+		column int
+		*xunsafe.Field
+	}
+)
+
+//ScanStruct reads next row into dest struct pointer, it returns io.EOF when there are no more rows,
+//columns are matched to fields by dynamodbav tag or case insensitive field name, unmatched columns are skipped
+func (r *Rows) ScanStruct(dest interface{}) error {
+	rType := reflect.TypeOf(dest)
+	if rType == nil || rType.Kind() != reflect.Ptr || rType.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("invalid scan destination: expected struct pointer, but had %T", dest)
+	}
+	if r.mapping == nil || r.mapping.rType != rType.Elem() {
+		r.mapping = newStructMapping(rType.Elem(), r.Columns(), r.state)
+		r.values = make([]driver.Value, len(r.Columns()))
+	}
+	data, err := r.nextRow()
+	if err != nil {
+		return err
+	}
+	ptr := xunsafe.AsPointer(dest)
+	if err = r.mapping.decoder.Decode(data, ptr, r.values); err != nil {
+		return err
+	}
+	return r.mapping.set(ptr, r.values)
+}
+
+func newStructMapping(rType reflect.Type, columns []string, state *exec.State) *structMapping {
+	var byName = make(map[string]*xunsafe.Field, rType.NumField())
+	for i := 0; i < rType.NumField(); i++ {
+		field := rType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("dynamodbav"); tag != "" {
+			if name = strings.Split(tag, ",")[0]; name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+		}
+		byName[strings.ToLower(name)] = xunsafe.NewField(field)
+	}
+	var matched = make(map[int]*xunsafe.Field, len(columns))
+	for i, column := range columns {
+		if field, ok := byName[strings.ToLower(column)]; ok {
+			matched[i] = field
+		}
+	}
+	result := &structMapping{rType: rType, decoder: exec.NewStructDecoder(state, matched)}
+	for i := range columns {
+		if field, ok := matched[i]; ok && !result.decoder.IsDirect(i) {
+			result.fields = append(result.fields, &structField{column: i, Field: field})
+		}
+	}
+	return result
+}
+
+//set assigns row values to fields which are not decoded directly, value with the field type is set as is, otherwise it is converted
+func (m *structMapping) set(ptr unsafe.Pointer, values []driver.Value) error {
+	for _, field := range m.fields {
+		value := values[field.column]
+		if value != nil && reflect.TypeOf(value) == field.Type {
+			switch field.Type.Kind() {
+			case reflect.String, reflect.Int, reflect.Int64, reflect.Float64, reflect.Float32, reflect.Bool:
+				field.Set(ptr, value)
+				continue
+			}
+		}
+		dest := reflect.NewAt(field.Type, field.Pointer(ptr)).Elem()
+		if err := assign(dest, value); err != nil {
+			return fmt.Errorf("failed to set %v.%v: %w", m.rType.Name(), field.Name, err)
+		}
+	}
+	return nil
+}
+
+func assign(dest reflect.Value, value interface{}) error {
+	if value == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+	source := reflect.ValueOf(value)
+	if dest.Kind() == reflect.Ptr && source.Type() != dest.Type() {
+		elem := reflect.New(dest.Type().Elem())
+		if err := assign(elem.Elem(), value); err != nil {
+			return err
+		}
+		dest.Set(elem)
+		return nil
+	}
+	switch {
+	case source.Type().AssignableTo(dest.Type()):
+		dest.Set(source)
+	case (source.Kind() == dest.Kind() || isNumber(source.Kind()) && isNumber(dest.Kind())) && source.Type().ConvertibleTo(dest.Type()):
+		dest.Set(source.Convert(dest.Type()))
+	default:
+		return fmt.Errorf("unable to assign %T to %v", value, dest.Type())
+	}
+	return nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+//queryRows runs SQL with connection acquired from db and calls fn with driver rows, args support sql.Named
+func queryRows(ctx context.Context, db *sql.DB, SQL string, args []interface{}, fn func(rows *Rows) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Raw(func(driverConn interface{}) error {
+		connection, ok := driverConn.(*Connection)
+		if !ok {
+			return fmt.Errorf("unsupported connection type: %T", driverConn)
+		}
+		stmt, err := connection.PrepareContext(ctx, SQL)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		statement := stmt.(*Statement)
+		var named = make([]driver.NamedValue, len(args))
+		for i, arg := range args {
+			named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+			if namedArg, ok := arg.(sql.NamedArg); ok {
+				named[i].Name, named[i].Value = namedArg.Name, namedArg.Value
+			}
+			if err = statement.CheckNamedValue(&named[i]); err != nil {
+				return err
+			}
+		}
+		rows, err := statement.QueryContext(ctx, named)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		return fn(rows.(*Rows))
+	})
+}
@@ -0,0 +1,79 @@
+package dyndb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	ndynamodb "github.com/viant/dyndb/internal/dynamodb"
+	"github.com/viant/dyndb/internal/exec"
+	"github.com/viant/sqlparser"
+	"io"
+	"testing"
+)
+
+func TestRows_ScanStruct(t *testing.T) {
+	type status string
+	type publication struct {
+		ISBN   string
+		Title  status `dynamodbav:"Name"`
+		Price  *float64
+		Views  int64
+		Rating float32
+		Active bool
+		Tags   []string
+		Ignore string `dynamodbav:"-"`
+	}
+	table := "Publication"
+	desc := &types.TableDescription{TableName: &table, KeySchema: []types.KeySchemaElement{{AttributeName: &table, KeyType: types.KeyTypeHash}}}
+	aQuery, err := sqlparser.ParseQuery("SELECT ISBN, Name, Price, Views, Rating, Active, Tags, Ignore FROM Publication")
+	if !assert.Nil(t, err) {
+		return
+	}
+	execution, err := exec.NewQuery(table, aQuery, desc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	items := []map[string]types.AttributeValue{
+		{
+			"ISBN":   &types.AttributeValueMemberS{Value: "1"},
+			"Name":   &types.AttributeValueMemberS{Value: "Title 1"},
+			"Price":  &types.AttributeValueMemberN{Value: "1.5"},
+			"Views":  &types.AttributeValueMemberN{Value: "3"},
+			"Rating": &types.AttributeValueMemberN{Value: "4.5"},
+			"Active": &types.AttributeValueMemberBOOL{Value: true},
+			"Tags":   &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+			"Ignore": &types.AttributeValueMemberS{Value: "x"},
+		},
+		{
+			"ISBN":  &types.AttributeValueMemberS{Value: "2"},
+			"Name":  &types.AttributeValueMemberS{Value: "Title 2"},
+			"Views": &types.AttributeValueMemberNULL{Value: true},
+		},
+	}
+	state := execution.NewQueryState(nil)
+	rows, err := (&Statement{execution: execution}).loadRows(state, ndynamodb.NewDeserializeMiddleware(state.Type), items, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var actual []publication
+	item := publication{}
+	for {
+		if err = rows.ScanStruct(&item); err != nil { //the same item is reused, direct fields missing in the row are reset
+			break
+		}
+		actual = append(actual, item)
+	}
+	assert.Equal(t, io.EOF, err)
+	price := 1.5
+	assert.EqualValues(t, []publication{
+		{ISBN: "1", Title: "Title 1", Price: &price, Views: 3, Rating: 4.5, Active: true, Tags: []string{"a", "b"}},
+		{ISBN: "2", Title: "Title 2", Tags: []string{}},
+	}, actual)
+	var direct []string
+	for i, column := range rows.Columns() {
+		if rows.mapping.decoder.IsDirect(i) {
+			direct = append(direct, column)
+		}
+	}
+	assert.EqualValues(t, []string{"ISBN", "Name", "Views", "Rating", "Active"}, direct)
+	assert.NotNil(t, rows.ScanStruct(publication{}))
+}
@@ -0,0 +1,134 @@
+package dyndb
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"sync"
+	"time"
+)
+
+type (
+	//schemaCache represents driver-wide table description cache of a DSN, shared by all its connections
+	schemaCache struct {
+		scope string
+		ttl   time.Duration
+	}
+
+	schemaKey struct {
+		scope string
+		table string
+	}
+
+	//schemaEntry represents cached description, ready is closed once description is loaded
+	schemaEntry struct {
+		desc   *types.TableDescription
+		err    error
+		expiry time.Time
+		ready  chan struct{}
+	}
+)
+
+var schemas = struct {
+	entries map[schemaKey]*schemaEntry
+	mux     sync.Mutex
+}{entries: map[schemaKey]*schemaEntry{}}
+
+//InvalidateSchema removes table description and executions cached by all connections, use it after the table has been changed outside of the driver
+func InvalidateSchema(table string) {
+	invalidateExecutions(table)
+	schemas.mux.Lock()
+	defer schemas.mux.Unlock()
+	for key := range schemas.entries {
+		if key.table == table {
+			delete(schemas.entries, key)
+		}
+	}
+}
+
+//describe returns cached table description, concurrent misses share one DescribeTable call, errors are not cached,
+//the shared call is not cancelled with the caller context, each caller stops waiting once its own context is done
+func (c *schemaCache) describe(ctx context.Context, client *dynamodb.Client, table string) (*types.TableDescription, error) {
+	if c == nil || c.ttl <= 0 {
+		return tableDescription(ctx, client, table)
+	}
+	key := schemaKey{scope: c.scope, table: table}
+	schemas.mux.Lock()
+	entry, ok := schemas.entries[key]
+	if ok {
+		select {
+		case <-entry.ready:
+			if time.Now().After(entry.expiry) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		entry = &schemaEntry{ready: make(chan struct{})}
+		schemas.entries[key] = entry
+		go c.load(context.WithoutCancel(ctx), client, key, entry)
+	}
+	schemas.mux.Unlock()
+	select {
+	case <-entry.ready:
+		return entry.desc, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+//load loads entry description, failed entry is removed
+func (c *schemaCache) load(ctx context.Context, client *dynamodb.Client, key schemaKey, entry *schemaEntry) {
+	entry.desc, entry.err = tableDescription(ctx, client, key.table)
+	entry.expiry = time.Now().Add(c.ttl)
+	if entry.err != nil {
+		schemas.mux.Lock()
+		if schemas.entries[key] == entry {
+			delete(schemas.entries, key)
+		}
+		schemas.mux.Unlock()
+	}
+	close(entry.ready)
+}
+
+//expiry returns expiry of loaded table description or zero time if it is not cached,
+//executions built from the description are cached until then
+func (c *schemaCache) expiry(table string) time.Time {
+	if c == nil || c.ttl <= 0 || table == "" {
+		return time.Time{}
+	}
+	schemas.mux.Lock()
+	entry, ok := schemas.entries[schemaKey{scope: c.scope, table: table}]
+	schemas.mux.Unlock()
+	if !ok {
+		return time.Time{}
+	}
+	select {
+	case <-entry.ready:
+		return entry.expiry
+	default:
+		return time.Time{}
+	}
+}
+
+//tableDescription returns table description with DescribeTable
+func tableDescription(ctx context.Context, client *dynamodb.Client, table string) (*types.TableDescription, error) {
+	var desc *types.TableDescription
+	describeOutput, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: &table})
+	if describeOutput != nil {
+		desc = describeOutput.Table
+	}
+	return desc, err
+}
+
+//optionalDescribe returns cached table description or nil if table does not exist
+func (c *schemaCache) optionalDescribe(ctx context.Context, client *dynamodb.Client, table string) (*types.TableDescription, error) {
+	desc, err := c.describe(ctx, client, table)
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+	return desc, err
+}
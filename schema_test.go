@@ -0,0 +1,168 @@
+package dyndb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchemaCache_Describe(t *testing.T) {
+	server := newTestServer(map[string]testHandler{"DescribeTable": func(input []byte) (string, error) {
+		time.Sleep(10 * time.Millisecond)
+		return `{"Table":{"TableName":"Users","KeySchema":[{"AttributeName":"Id","KeyType":"HASH"}]}}`, nil
+	}})
+	defer server.Close()
+	client := server.Client()
+
+	var testCases = []struct {
+		description string
+		ttl         time.Duration
+		invalidate  bool
+		expect      int
+	}{
+		{description: "cached", ttl: time.Minute, expect: 1},
+		{description: "invalidated", ttl: time.Minute, invalidate: true, expect: 2},
+		{description: "expired", ttl: 50 * time.Millisecond, expect: 2},
+		{description: "disabled", expect: 8},
+	}
+
+	ctx := context.Background()
+	for i, testCase := range testCases {
+		InvalidateSchema("Users")
+		server.Reset()
+		cache := &schemaCache{scope: testCase.description, ttl: testCase.ttl}
+		for batch := 0; batch < 2; batch++ {
+			if batch == 1 && testCase.invalidate {
+				InvalidateSchema("Users")
+			}
+			if batch == 1 && testCase.ttl < time.Second {
+				time.Sleep(testCase.ttl)
+			}
+			var wg sync.WaitGroup
+			for j := 0; j < 4; j++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					desc, err := cache.describe(ctx, client, "Users")
+					if assert.Nil(t, err, testCase.description) {
+						assert.EqualValues(t, "Users", *desc.TableName, testCase.description)
+					}
+				}()
+			}
+			wg.Wait()
+		}
+		assert.EqualValues(t, testCase.expect, server.Calls("DescribeTable"), testCases[i].description)
+	}
+}
+
+func TestStatement_CachedDescribe(t *testing.T) {
+	server := newTestServer(map[string]testHandler{
+		"ListTables": staticOutput(`{"TableNames":["Users"]}`),
+		"DescribeTable": func(input []byte) (string, error) {
+			request := struct{ TableName string }{}
+			_ = json.Unmarshal(input, &request)
+			if request.TableName != "Users" {
+				return "", testError("ResourceNotFoundException")
+			}
+			return `{"Table":{"TableName":"Users","KeySchema":[{"AttributeName":"Id","KeyType":"HASH"}],"AttributeDefinitions":[{"AttributeName":"Id","AttributeType":"N"}]}}`, nil
+		},
+	})
+	defer server.Close()
+
+	var testCases = []struct {
+		description string
+		SQL         string
+		expectRows  int
+		expectCalls int
+	}{
+		{description: "describe", SQL: "DESCRIBE Users", expectRows: 1, expectCalls: 1},
+		{description: "show indexes", SQL: "SHOW INDEXES FROM Users", expectRows: 1, expectCalls: 1},
+		{description: "information schema", SQL: "SELECT TABLE_NAME FROM information_schema.tables", expectRows: 1, expectCalls: 1},
+		{description: "missing table", SQL: "SELECT TABLE_NAME FROM information_schema.tables WHERE TABLE_NAME IN ('Users', 'Orders')", expectRows: 1, expectCalls: 3},
+	}
+
+	for _, testCase := range testCases {
+		InvalidateSchema("Users")
+		server.Reset()
+		db, err := server.Open("schemaTTL=1m")
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		for i := 0; i < 2; i++ {
+			rows, err := db.Query(testCase.SQL)
+			if !assert.Nil(t, err, testCase.description) {
+				break
+			}
+			count := 0
+			for rows.Next() {
+				count++
+			}
+			assert.Nil(t, rows.Err(), testCase.description)
+			_ = rows.Close()
+			assert.EqualValues(t, testCase.expectRows, count, testCase.description)
+		}
+		assert.EqualValues(t, testCase.expectCalls, server.Calls("DescribeTable"), testCase.description)
+		_ = db.Close()
+	}
+}
+
+func TestSchemaCache_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	server := newTestServer(map[string]testHandler{"DescribeTable": func(input []byte) (string, error) {
+		<-release
+		return `{"Table":{"TableName":"Users","KeySchema":[{"AttributeName":"Id","KeyType":"HASH"}]}}`, nil
+	}})
+	defer server.Close()
+	InvalidateSchema("Users")
+	cache := &schemaCache{scope: "cancel", ttl: time.Minute}
+	client := server.Client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.describe(ctx, client, "Users")
+		first <- err
+	}()
+	for server.Calls("DescribeTable") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	second := make(chan error, 1)
+	go func() {
+		desc, err := cache.describe(context.Background(), client, "Users")
+		if err == nil && *desc.TableName != "Users" {
+			err = fmt.Errorf("unexpected table: %v", *desc.TableName)
+		}
+		second <- err
+	}()
+	cancel()
+	assert.True(t, errors.Is(<-first, context.Canceled))
+	close(release)
+	assert.Nil(t, <-second)
+	assert.EqualValues(t, 1, server.Calls("DescribeTable"))
+}
+
+func TestStatement_ExecutionExpiry(t *testing.T) {
+	server := newTestServer(publicationPages())
+	defer server.Close()
+	InvalidateSchema("Publication")
+	db, err := server.Open("schemaTTL=50ms")
+	if !assert.Nil(t, err) {
+		return
+	}
+	for i := 0; i < 3; i++ {
+		if i == 2 {
+			time.Sleep(60 * time.Millisecond)
+		}
+		rows, err := db.Query("SELECT ISBN FROM Publication WHERE ISBN = ?", "0-1")
+		if !assert.Nil(t, err) {
+			return
+		}
+		_ = rows.Close()
+	}
+	assert.EqualValues(t, 2, server.Calls("DescribeTable"))
+}
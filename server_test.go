@@ -0,0 +1,178 @@
+package dyndb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	//testServer represents fake DynamoDB and DynamoDB Streams endpoint, requests are routed to handlers by X-Amz-Target operation
+	testServer struct {
+		*httptest.Server
+		handlers map[string]testHandler
+		mux      sync.Mutex
+		calls    map[string]int
+		inputs   map[string][]string
+		dbs      []*sql.DB
+	}
+
+	//testHandler returns operation JSON output for JSON input, testError is written as DynamoDB error response
+	testHandler func(input []byte) (string, error)
+
+	//testError represents DynamoDB error response, i.e. ResourceNotFoundException
+	testError string
+)
+
+//publicationTable represents Publication table DescribeTable output
+const publicationTable = `{"Table":{"TableName":"Publication","KeySchema":[{"AttributeName":"ISBN","KeyType":"HASH"}],"AttributeDefinitions":[{"AttributeName":"ISBN","AttributeType":"S"}]}}`
+
+//Error returns error message
+func (e testError) Error() string {
+	return string(e)
+}
+
+func newTestServer(handlers map[string]testHandler) *testServer {
+	result := &testServer{handlers: handlers, calls: map[string]int{}, inputs: map[string][]string{}}
+	result.Server = httptest.NewServer(http.HandlerFunc(result.handle))
+	return result
+}
+
+func (s *testServer) handle(writer http.ResponseWriter, request *http.Request) {
+	target := request.Header.Get("X-Amz-Target")
+	operation := target[strings.LastIndexByte(target, '.')+1:]
+	input, _ := io.ReadAll(request.Body)
+	s.mux.Lock()
+	s.calls[operation]++
+	s.inputs[operation] = append(s.inputs[operation], string(input))
+	handler, ok := s.handlers[operation]
+	s.mux.Unlock()
+	writer.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	if !ok {
+		s.writeError(writer, testError("UnknownOperationException"))
+		return
+	}
+	output, err := handler(input)
+	if err != nil {
+		s.writeError(writer, err)
+		return
+	}
+	_, _ = writer.Write([]byte(output))
+}
+
+func (s *testServer) writeError(writer http.ResponseWriter, err error) {
+	code := "InternalServerError"
+	var errorType testError
+	if errors.As(err, &errorType) {
+		code = string(errorType)
+	}
+	writer.Header().Set("X-Amzn-Errortype", code)
+	writer.WriteHeader(http.StatusBadRequest)
+	_, _ = fmt.Fprintf(writer, `{"__type":"com.amazonaws.dynamodb.v20120810#%v","message":%q}`, code, err.Error())
+}
+
+//Calls returns number of operation requests
+func (s *testServer) Calls(operation string) int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.calls[operation]
+}
+
+//Statements returns PartiQL statements of ExecuteStatement requests
+func (s *testServer) Statements() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var result []string
+	for _, input := range s.inputs["ExecuteStatement"] {
+		request := struct{ Statement string }{}
+		_ = json.Unmarshal([]byte(input), &request)
+		result = append(result, request.Statement)
+	}
+	return result
+}
+
+//Reset resets operation request counters and inputs
+func (s *testServer) Reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.calls = map[string]int{}
+	s.inputs = map[string][]string{}
+}
+
+//Open returns db connected to the server, options are appended to DSN query string, db is closed with the server
+func (s *testServer) Open(options ...string) (*sql.DB, error) {
+	DSN := "dynamodb://" + strings.TrimPrefix(s.URL, "http://") + "/us-west-1?key=dummy&secret=dummy"
+	for _, option := range options {
+		DSN += "&" + option
+	}
+	db, err := sql.Open("dynamodb", DSN)
+	if err != nil {
+		return nil, err
+	}
+	s.mux.Lock()
+	s.dbs = append(s.dbs, db)
+	s.mux.Unlock()
+	return db, nil
+}
+
+//Close closes opened dbs and the server
+func (s *testServer) Close() {
+	s.mux.Lock()
+	dbs := s.dbs
+	s.dbs = nil
+	s.mux.Unlock()
+	for _, db := range dbs {
+		_ = db.Close()
+	}
+	s.Server.Close()
+}
+
+//Client returns DynamoDB client connected to the server
+func (s *testServer) Client() *dynamodb.Client {
+	return dynamodb.New(dynamodb.Options{
+		Region:      "us-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		//resolver sets missing signing region without a lock
+		EndpointResolver: dynamodb.EndpointResolverFromURL(s.URL, func(endpoint *aws.Endpoint) { endpoint.SigningRegion = "us-west-1" }),
+	})
+}
+
+//staticOutput returns handler writing supplied output
+func staticOutput(output string) testHandler {
+	return func(input []byte) (string, error) {
+		return output, nil
+	}
+}
+
+//itemPages returns ExecuteStatement handler writing pages of supplied comma separated items linked with NextToken
+func itemPages(pages ...string) testHandler {
+	return func(input []byte) (string, error) {
+		request := struct{ NextToken string }{}
+		_ = json.Unmarshal(input, &request)
+		page, _ := strconv.Atoi(request.NextToken)
+		output := `{"Items":[` + pages[page] + "]"
+		if page+1 < len(pages) {
+			output += fmt.Sprintf(`,"NextToken":"%v"`, page+1)
+		}
+		return output + `,"ConsumedCapacity":{"TableName":"Publication","CapacityUnits":0.5}}`, nil
+	}
+}
+
+//publicationPages returns Publication table handlers with 3 pages of 2 items
+func publicationPages() map[string]testHandler {
+	var pages []string
+	for page := 0; page < 3; page++ {
+		pages = append(pages, fmt.Sprintf(`{"ISBN":{"S":"%v-1"},"Price":{"N":"%v"}},{"ISBN":{"S":"%v-2"},"Price":{"N":"1"}}`, page, page, page))
+	}
+	return map[string]testHandler{"DescribeTable": staticOutput(publicationTable), "ExecuteStatement": itemPages(pages...)}
+}
@@ -3,8 +3,10 @@ package dyndb
 import (
 	"context"
 	"database/sql/driver"
+	"fmt"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
 	ndynamodb "github.com/viant/dyndb/internal/dynamodb"
 	"github.com/viant/dyndb/internal/exec"
 
@@ -17,10 +19,16 @@ var maxWaitTime = 30 * time.Second
 
 //Statement abstraction implements database/sql driver.Statement interface
 type Statement struct {
-	token     *string
-	execution *exec.Execution
-	state     *exec.State
-	client    *dynamodb.Client
+	token          *string
+	execution      *exec.Execution
+	state          *exec.State
+	client         *dynamodb.Client
+	streams        *dynamodbstreams.Client
+	schemas        *schemaCache
+	consistentRead bool
+	wildcardPages  int
+	stats          *ConsumedCapacity
+	observer       *observer
 }
 
 //Exec executes statements
@@ -31,28 +39,202 @@ func (s *Statement) Exec(args []driver.Value) (driver.Result, error) {
 
 //ExecContext executes statements
 func (s *Statement) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	started := time.Now()
+	event := s.event(args, 0)
+	args, err := s.execution.BindNamed(args)
+	var aResult driver.Result
+	if err == nil {
+		aResult, err = s.execContext(ctx, args)
+	}
+	if err != nil {
+		err = s.wrapError(err)
+		event.Err = err
+	} else if ret, ok := aResult.(*result); ok {
+		event.Items = int(ret.totalRows)
+		event.ConsumedCapacity = ret.consumed
+	}
+	s.observer.observe(ctx, started, event)
+	if err != nil {
+		return nil, err
+	}
+	return aResult, nil
+}
+
+//event returns observer event
+func (s *Statement) event(args []driver.NamedValue, page int) *Event {
+	result := &Event{SQL: s.execution.SQL, Parameters: len(args), Page: page}
+	if s.execution.Parti != nil {
+		result.PartiQL = s.execution.Parti.Query
+	}
+	return result
+}
+
+func (s *Statement) execContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
 	switch s.execution.Kind {
 	case exec.KindCreateTable:
 		return s.createTable(ctx)
 	case exec.KindDropTable:
 		return s.dropTable(ctx)
+	case exec.KindCopyFrom:
+		consumed := NewConsumedCapacity()
+		return s.copyFrom(ctx, s.collectors(ctx, consumed))
+	case exec.KindCopyTo:
+		return s.copyTo(ctx, args)
+	}
+	if s.execution.IsMeta() {
+		return &result{}, nil
 	}
 	state := s.execution.NewState(args)
 	s.state = state
-	ql := s.execution.Parti.Query
-	parameters, err := state.ProjectionListParameters()
+	consumed := NewConsumedCapacity()
+	collectors := s.collectors(ctx, consumed)
+	if s.execution.Kind == exec.KindInsert {
+		parameters, err := state.Parameters()
+		if err != nil {
+			return nil, err
+		}
+		affected, _, err := s.insert(ctx, state, parameters, collectors)
+		if err != nil {
+			return nil, err
+		}
+		return &result{totalRows: affected, consumed: consumed}, nil
+	}
+	requests, err := state.Requests(s.execution.Parti.Query)
 	if err != nil {
 		return nil, err
 	}
-	if err = s.exec(ctx, ql, parameters); err != nil {
-		return nil, err
+	var affected int64
+	for _, request := range requests {
+		output, err := s.exec(ctx, request.Query, request.Parameters, collectors)
+		if err != nil {
+			return nil, err
+		}
+		affected += s.affectedItems(output)
+	}
+	return &result{totalRows: affected, consumed: consumed}, nil
+}
+
+//affectedItems returns number of items written by UPDATE or DELETE request, PartiQL UPDATE and DELETE address a single item by its key,
+//with RETURNING the returned items tell whether the item existed, otherwise a successful request counts as one item
+func (s *Statement) affectedItems(output *dynamodb.ExecuteStatementOutput) int64 {
+	if s.execution.Returning != nil {
+		return int64(len(output.Items))
 	}
-	return &result{totalRows: 1}, err
+	return 1
+}
+
+//collectors returns statement, connection and context consumed capacity collectors
+func (s *Statement) collectors(ctx context.Context, consumed *ConsumedCapacity) capacities {
+	var result = capacities{consumed}
+	if s.stats != nil {
+		result = append(result, s.stats)
+	}
+	if collector, ok := ctx.Value(consumedCapacityKey).(*ConsumedCapacity); ok && collector != nil {
+		result = append(result, collector)
+	}
+	return result
+}
+
+//wrapError returns classified driver error, versioned write condition failure is reported as ErrOptimisticLock
+func (s *Statement) wrapError(err error) error {
+	PartiQL := ""
+	if s.execution.Parti != nil {
+		PartiQL = s.execution.Parti.Query
+	}
+	result := newError(err, s.execution.SQL, PartiQL).(*Error)
+	if s.execution.Version != "" && result.Kind == ErrConditionFailed {
+		result.Kind = ErrOptimisticLock
+	}
+	return result
+}
+
+//insert writes an item, it returns affected rows and written items when RETURNING clause is used
+func (s *Statement) insert(ctx context.Context, state *exec.State, parameters []types.AttributeValue, collectors capacities) (int64, []map[string]types.AttributeValue, error) {
+	execution := s.execution
+	var items []map[string]types.AttributeValue
+	if execution.Replace || execution.Returning != nil {
+		item, err := execution.Item(state)
+		if err != nil {
+			return 0, nil, err
+		}
+		items = append(items, item)
+	}
+	if execution.Replace {
+		output, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:              &execution.Table,
+			Item:                   items[0],
+			ReturnConsumedCapacity: types.ReturnConsumedCapacityIndexes,
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+		collectors.add(output.ConsumedCapacity, true)
+		return 1, items, nil
+	}
+	_, err := s.exec(ctx, execution.Parti.Query, parameters, collectors)
+	if err == nil {
+		return 1, items, nil
+	}
+	if kind, _ := classify(err); kind != ErrDuplicateKey {
+		return 0, nil, err
+	}
+	conflict := execution.Conflict
+	if conflict == nil {
+		return 0, nil, &Error{Kind: ErrDuplicateKey, Item: s.existingItem(ctx, state, collectors), Err: err}
+	}
+	if conflict.DoNothing {
+		return 0, nil, nil
+	}
+	args, err := execution.ConflictArgs(state)
+	if err != nil {
+		return 0, nil, err
+	}
+	updateState := conflict.Update.NewState(args)
+	defer conflict.Update.ReleaseState(updateState)
+	if parameters, err = updateState.Parameters(); err != nil {
+		return 0, nil, err
+	}
+	output, err := s.client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement:              &conflict.Update.Parti.Query,
+		Parameters:             parameters,
+		ReturnConsumedCapacity: types.ReturnConsumedCapacityIndexes,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	collectors.add(output.ConsumedCapacity, true)
+	return 1, output.Items, nil
+}
+
+//existingItem returns stored item with inserted item key or nil if it can not be read
+func (s *Statement) existingItem(ctx context.Context, state *exec.State, collectors capacities) map[string]types.AttributeValue {
+	key, err := s.execution.Key(state)
+	if err != nil {
+		return nil
+	}
+	consistentRead := true
+	output, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:              &s.execution.Table,
+		Key:                    key,
+		ConsistentRead:         &consistentRead,
+		ReturnConsumedCapacity: types.ReturnConsumedCapacityIndexes,
+	})
+	if err != nil {
+		return nil
+	}
+	collectors.add(output.ConsumedCapacity, false)
+	return output.Item
 }
 
 func (s *Statement) createTable(ctx context.Context) (driver.Result, error) {
-	if s.execution.HasTable && s.execution.Create.IfDoesExists {
-		return &result{}, nil
+	if s.execution.Create.IfDoesExists {
+		desc, err := s.schemas.optionalDescribe(ctx, s.client, s.execution.Table)
+		if err != nil {
+			return nil, err
+		}
+		if desc != nil {
+			return &result{}, nil
+		}
 	}
 	input, err := s.execution.CreateTableInput()
 	if err != nil {
@@ -60,6 +242,7 @@ func (s *Statement) createTable(ctx context.Context) (driver.Result, error) {
 	}
 
 	output, err := s.client.CreateTable(ctx, input)
+	InvalidateSchema(s.execution.Table)
 	if output != nil {
 		description := output.TableDescription
 		startTime := time.Now()
@@ -69,13 +252,20 @@ func (s *Statement) createTable(ctx context.Context) (driver.Result, error) {
 				break
 			}
 		}
+		InvalidateSchema(s.execution.Table) //status polling is not cached, descriptions loaded meanwhile may be stale
 	}
 	return nil, err
 }
 
 func (s *Statement) dropTable(ctx context.Context) (driver.Result, error) {
-	if !s.execution.HasTable && s.execution.Drop.IfExists {
-		return &result{}, nil
+	if s.execution.Drop.IfExists {
+		desc, err := s.schemas.optionalDescribe(ctx, s.client, s.execution.Table)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil {
+			return &result{}, nil
+		}
 	}
 	input, err := s.execution.DeleteTableInput()
 	if err != nil {
@@ -84,6 +274,7 @@ func (s *Statement) dropTable(ctx context.Context) (driver.Result, error) {
 	if _, err = s.client.DeleteTable(ctx, input); err != nil {
 		return nil, err
 	}
+	InvalidateSchema(s.execution.Table)
 	startTime := time.Now()
 	for time.Now().Sub(startTime) < maxWaitTime {
 		time.Sleep(100 * time.Millisecond)
@@ -91,6 +282,7 @@ func (s *Statement) dropTable(ctx context.Context) (driver.Result, error) {
 			break
 		}
 	}
+	InvalidateSchema(s.execution.Table) //status polling is not cached, descriptions loaded meanwhile may be stale
 	return nil, err
 }
 
@@ -110,45 +302,181 @@ func asNamedValues(args []driver.Value) []driver.NamedValue {
 
 //QueryContext runs query
 func (s *Statement) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	ql := s.execution.Parti.Query
-	deserializer := ndynamodb.NewDeserializeMiddleware(s.execution.Type)
-	state := s.execution.NewState(args)
-	parameters, err := state.QueryParameters()
+	started := time.Now()
+	event := s.event(args, 1)
+	args, err := s.execution.BindNamed(args)
+	var rows *Rows
+	if err == nil {
+		rows, err = s.queryContext(ctx, args)
+	}
+	if err != nil {
+		err = s.wrapError(err)
+		event.Err = err
+	} else {
+		event.Items = len(rows.deserializer.Output.Rows)
+		event.ConsumedCapacity = rows.pageCapacity
+		rows.continuation, _ = ctx.Value(continuationKey).(*Continuation)
+		rows.updateContinuation()
+	}
+	s.observer.observe(ctx, started, event)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *Statement) queryContext(ctx context.Context, args []driver.NamedValue) (*Rows, error) {
+	state := s.execution.NewQueryState(args)
+	deserializer := ndynamodb.NewDeserializeMiddleware(state.Type)
+	if s.execution.Kind == exec.KindStream {
+		return s.streamRows(ctx, state, deserializer, args)
+	}
+	if s.execution.IsMeta() {
+		items, err := s.metaItems(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return s.loadRows(state, deserializer, items, nil)
+	}
+	if s.execution.Parti == nil {
+		return nil, fmt.Errorf("%w: statement does not return rows: %v", exec.ErrInvalid, s.execution.SQL)
+	}
+	consumed := NewConsumedCapacity()
+	collectors := s.collectors(ctx, consumed)
+	if s.execution.Kind == exec.KindInsert {
+		parameters, err := state.Parameters()
+		if err != nil {
+			return nil, err
+		}
+		return s.insertReturning(ctx, state, deserializer, parameters, collectors)
+	}
+	requests, err := state.Requests(s.execution.Parti.Query)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := queryHash(s.execution.Parti.Query, requests)
 	if err != nil {
 		return nil, err
 	}
+	position, err := startPosition(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if position.Request > len(requests) {
+		return nil, fmt.Errorf("%w: continuation token request %v out of range", exec.ErrInvalid, position.Request)
+	}
+	remaining := requests[position.Request:]
+	if len(remaining) == 0 { //empty IN list
+		return s.loadRows(state, deserializer, nil, consumed)
+	}
 	rows := &Rows{client: s.client,
-		state:        state,
-		deserializer: deserializer,
-		execution:    s.execution,
-		ql:           ql,
-		parameters:   parameters,
-		limit:        s.execution.Limit,
+		state:          state,
+		deserializer:   deserializer,
+		execution:      s.execution,
+		ql:             remaining[0].Query,
+		parameters:     remaining[0].Parameters,
+		pending:        remaining[1:],
+		request:        position.Request,
+		requests:       len(requests),
+		queryHash:      hash,
+		limit:          s.execution.Limit,
+		consistentRead: s.isConsistentRead(ctx),
+		consumed:       consumed,
+		collectors:     collectors,
+		ctx:            ctx,
+		observer:       s.observer,
+		event:          s.event(args, 1),
 	}
 
+	if position.Token != "" {
+		rows.nextToken = &position.Token
+	}
 	if err := rows.executeQueryStatement(ctx); err != nil {
 		return nil, err
 	}
+	if rows.index = position.Skip; rows.index > len(deserializer.Output.Rows) {
+		rows.index = len(deserializer.Output.Rows)
+	}
+	if state.Type.Wildcard && !state.Type.IsResolved() {
+		if err = rows.sampleWildcard(ctx, s.wildcardPages); err != nil {
+			return nil, err
+		}
+	}
+	rows.startPrefetch(ctx)
 	err = state.Init()
 	return rows, err
 }
 
-func (s *Statement) exec(ctx context.Context, query string, parameters []types.AttributeValue) error {
-	_, err := s.client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
-		Statement:  &query,
-		Parameters: parameters,
+//isConsistentRead returns true if SELECT uses strongly consistent read, context option takes precedence over query hint and DSN default
+func (s *Statement) isConsistentRead(ctx context.Context) *bool {
+	if s.execution.Kind != exec.KindUndefined {
+		return nil
+	}
+	if value, ok := ctx.Value(consistentReadKey).(bool); ok {
+		return &value
+	}
+	if s.execution.ConsistentRead != nil {
+		return s.execution.ConsistentRead
+	}
+	if s.consistentRead {
+		return &s.consistentRead
+	}
+	return nil
+}
+
+//insertReturning writes an item and returns it, PartiQL INSERT does not support RETURNING
+func (s *Statement) insertReturning(ctx context.Context, state *exec.State, deserializer *ndynamodb.DeserializeMiddleware, parameters []types.AttributeValue, collectors capacities) (*Rows, error) {
+	if s.execution.Returning == nil {
+		return nil, fmt.Errorf("%w: INSERT without RETURNING clause does not return rows", exec.ErrInvalid)
+	}
+	_, items, err := s.insert(ctx, state, parameters, collectors)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadRows(state, deserializer, items, collectors[0])
+}
+
+//loadRows returns rows with client side supplied items
+func (s *Statement) loadRows(state *exec.State, deserializer *ndynamodb.DeserializeMiddleware, items []map[string]types.AttributeValue, consumed *ConsumedCapacity) (*Rows, error) {
+	if err := deserializer.Output.Load(items...); err != nil {
+		return nil, err
+	}
+	rows := &Rows{client: s.client,
+		state:        state,
+		deserializer: deserializer,
+		execution:    s.execution,
+		consumed:     consumed,
+		pageCapacity: consumed,
+	}
+	return rows, state.Init()
+}
+
+func (s *Statement) exec(ctx context.Context, query string, parameters []types.AttributeValue, collectors capacities) (*dynamodb.ExecuteStatementOutput, error) {
+	output, err := s.client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement:              &query,
+		Parameters:             parameters,
+		ReturnConsumedCapacity: types.ReturnConsumedCapacityIndexes,
 	})
-	return err
+	if err != nil {
+		return nil, err
+	}
+	collectors.add(output.ConsumedCapacity, true)
+	return output, nil
 }
 
-//CheckNamedValue checks supported types (all for now)
+//CheckNamedValue normalizes argument with exec.NormalizeArg, unsupported types are rejected
 func (s *Statement) CheckNamedValue(named *driver.NamedValue) error {
+	value, err := exec.NormalizeArg(named.Value)
+	if err != nil {
+		return fmt.Errorf("%w: argument %v: %v", exec.ErrInvalid, named.Ordinal, err)
+	}
+	named.Value = value
 	return nil
 }
 
 //NumInput returns numinput
 func (s *Statement) NumInput() int {
-	return s.execution.Type.NumInput()
+	return s.execution.NumInput()
 }
 
 //Close closes statement
@@ -2,10 +2,15 @@ package dyndb
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/assertly"
 	"github.com/viant/toolbox"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -336,3 +341,349 @@ outer:
 	}
 
 }
+
+func TestStatement_Upsert(t *testing.T) {
+	var duplicate bool
+	server := newTestServer(map[string]testHandler{
+		"DescribeTable": staticOutput(publicationTable),
+		"PutItem":       staticOutput(`{}`),
+		"GetItem":       staticOutput(`{"Item":{"ISBN":{"S":"AAA"},"Name":{"S":"Stored"}}}`),
+		"ExecuteStatement": func(input []byte) (string, error) {
+			if duplicate && strings.Contains(string(input), `"Statement":"INSERT`) {
+				return "", testError("DuplicateItemException")
+			}
+			return `{"Items":[]}`, nil
+		},
+	})
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var testCases = []struct {
+		description      string
+		SQL              string
+		args             []interface{}
+		duplicate        bool
+		expectAffected   int64
+		expectStatements []string
+		expectPutItem    int
+		expectErr        error
+		expectItem       map[string]string
+	}{
+		{
+			description:      "insert",
+			SQL:              "INSERT INTO Publication(ISBN, Name) VALUES(?, ?)",
+			args:             []interface{}{"AAA", "Go"},
+			expectAffected:   1,
+			expectStatements: []string{"INSERT INTO Publication VALUE {'ISBN':?,'Name':?}"},
+		},
+		{
+			description:      "duplicate key",
+			SQL:              "INSERT INTO Publication(ISBN, Name) VALUES(?, ?)",
+			args:             []interface{}{"AAA", "Go"},
+			duplicate:        true,
+			expectStatements: []string{"INSERT INTO Publication VALUE {'ISBN':?,'Name':?}"},
+			expectErr:        ErrDuplicateKey,
+			expectItem:       map[string]string{"ISBN": "AAA", "Name": "Stored"},
+		},
+		{
+			description:      "on conflict do nothing",
+			SQL:              "INSERT INTO Publication(ISBN, Name) VALUES(?, ?) ON CONFLICT DO NOTHING",
+			args:             []interface{}{"AAA", "Go"},
+			duplicate:        true,
+			expectStatements: []string{"INSERT INTO Publication VALUE {'ISBN':?,'Name':?}"},
+		},
+		{
+			description:      "on conflict do update",
+			SQL:              "INSERT INTO Publication(ISBN, Views) VALUES(?, 1) ON CONFLICT (ISBN) DO UPDATE SET Views = Views + ?",
+			args:             []interface{}{"AAA", 2},
+			duplicate:        true,
+			expectAffected:   1,
+			expectStatements: []string{"INSERT INTO Publication VALUE {'ISBN':?,'Views':1}", "UPDATE Publication SET Views = Views + ? WHERE ISBN = ?"},
+		},
+		{
+			description:    "replace",
+			SQL:            "REPLACE INTO Publication(ISBN, Name) VALUES(?, ?)",
+			args:           []interface{}{"AAA", "Go"},
+			expectAffected: 1,
+			expectPutItem:  1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		server.Reset()
+		duplicate = testCase.duplicate
+		result, err := db.Exec(testCase.SQL, testCase.args...)
+		assert.EqualValues(t, testCase.expectStatements, server.Statements(), testCase.description)
+		assert.EqualValues(t, testCase.expectPutItem, server.Calls("PutItem"), testCase.description)
+		if testCase.expectErr != nil {
+			assert.True(t, errors.Is(err, testCase.expectErr), testCase.description)
+			var driverErr *Error
+			if assert.True(t, errors.As(err, &driverErr), testCase.description) {
+				actual := map[string]string{}
+				for name, value := range driverErr.Item {
+					if text, ok := value.(*types.AttributeValueMemberS); ok {
+						actual[name] = text.Value
+					}
+				}
+				assert.EqualValues(t, testCase.expectItem, actual, testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		affected, _ := result.RowsAffected()
+		assert.EqualValues(t, testCase.expectAffected, affected, testCase.description)
+	}
+}
+
+func TestStatement_Returning(t *testing.T) {
+	server := newTestServer(map[string]testHandler{
+		"DescribeTable":    staticOutput(publicationTable),
+		"ExecuteStatement": staticOutput(`{"Items":[{"ISBN":{"S":"AAA"},"Name":{"S":"Go 2"},"Views":{"N":"3"}}]}`),
+	})
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var testCases = []struct {
+		description     string
+		SQL             string
+		args            []interface{}
+		expect          []string
+		expectStatement string
+	}{
+		{
+			description:     "insert returns written item",
+			SQL:             "INSERT INTO Publication(ISBN, Name) VALUES(?, ?) RETURNING ISBN, Name",
+			args:            []interface{}{"AAA", "Go"},
+			expect:          []string{"AAA", "Go"},
+			expectStatement: "INSERT INTO Publication VALUE {'ISBN':?,'Name':?}",
+		},
+		{
+			description:     "update returns new item",
+			SQL:             "UPDATE Publication SET Name = ? WHERE ISBN = ? RETURNING ISBN, Name",
+			args:            []interface{}{"Go 2", "AAA"},
+			expect:          []string{"AAA", "Go 2"},
+			expectStatement: "UPDATE Publication SET Name = ? WHERE ISBN = ? RETURNING ALL NEW *",
+		},
+		{
+			description:     "delete returns old item",
+			SQL:             "DELETE FROM Publication WHERE ISBN = ? RETURNING ISBN, Name",
+			args:            []interface{}{"AAA"},
+			expect:          []string{"AAA", "Go 2"},
+			expectStatement: "DELETE FROM Publication WHERE ISBN = ? RETURNING ALL OLD *",
+		},
+	}
+
+	for _, testCase := range testCases {
+		server.Reset()
+		rows, err := db.Query(testCase.SQL, testCase.args...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []string
+		for rows.Next() {
+			var ISBN, name string
+			if assert.Nil(t, rows.Scan(&ISBN, &name), testCase.description) {
+				actual = append(actual, ISBN, name)
+			}
+		}
+		assert.Nil(t, rows.Err(), testCase.description)
+		assert.Nil(t, rows.Close(), testCase.description)
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+		assert.EqualValues(t, []string{testCase.expectStatement}, server.Statements(), testCase.description)
+	}
+}
+
+func TestStatement_Version(t *testing.T) {
+	version := 0 //stored item version, 0 if item does not exist
+	server := newTestServer(map[string]testHandler{
+		"DescribeTable": staticOutput(publicationTable),
+		"ExecuteStatement": func(input []byte) (string, error) {
+			request := struct {
+				Statement  string
+				Parameters []struct{ N string }
+			}{}
+			_ = json.Unmarshal(input, &request)
+			expected := -1
+			if strings.Contains(request.Statement, "AND Version = ?") {
+				expected, _ = strconv.Atoi(request.Parameters[len(request.Parameters)-1].N)
+			}
+			switch {
+			case strings.HasPrefix(request.Statement, "INSERT"):
+				if version > 0 {
+					return "", testError("DuplicateItemException")
+				}
+				if !strings.Contains(request.Statement, "'Version':1") {
+					return "", fmt.Errorf("missing version: %v", request.Statement)
+				}
+				version = 1
+			case version == 0 || (expected != -1 && expected != version):
+				return "", testError("ConditionalCheckFailedException")
+			case strings.HasPrefix(request.Statement, "DELETE"):
+				version = 0
+			case strings.Contains(request.Statement, "SET Version = Version + 1"):
+				version++
+			}
+			return `{"Items":[]}`, nil
+		},
+	})
+	defer server.Close()
+	db, err := server.Open("version=Publication:Version")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	//steps share the stored item
+	var testCases = []struct {
+		description      string
+		SQL              string
+		args             []interface{}
+		expectStatements []string
+		expectVersion    int
+		expectErr        error
+	}{
+		{
+			description:      "insert starts with version 1",
+			SQL:              "INSERT INTO Publication(ISBN, Name) VALUES(?, ?)",
+			args:             []interface{}{"AAA", "Go"},
+			expectStatements: []string{"INSERT INTO Publication VALUE {'ISBN':?,'Name':?,'Version':1}"},
+			expectVersion:    1,
+		},
+		{
+			description:      "update inserted item",
+			SQL:              "UPDATE Publication SET Name = ? WHERE ISBN = ?",
+			args:             []interface{}{"Go 2", "AAA", 1},
+			expectStatements: []string{"UPDATE Publication SET Name = ? SET Version = Version + 1 WHERE (ISBN = ?) AND Version = ?"},
+			expectVersion:    2,
+		},
+		{
+			description:      "stale update",
+			SQL:              "UPDATE Publication SET Name = ? WHERE ISBN = ?",
+			args:             []interface{}{"Go 3", "AAA", 1},
+			expectStatements: []string{"UPDATE Publication SET Name = ? SET Version = Version + 1 WHERE (ISBN = ?) AND Version = ?"},
+			expectVersion:    2,
+			expectErr:        ErrOptimisticLock,
+		},
+		{
+			description:      "upsert increments version",
+			SQL:              "INSERT INTO Publication(ISBN, Name) VALUES(?, ?) ON CONFLICT (ISBN) DO UPDATE SET Name = ?",
+			args:             []interface{}{"AAA", "Go", "Go 3"},
+			expectStatements: []string{"INSERT INTO Publication VALUE {'ISBN':?,'Name':?,'Version':1}", "UPDATE Publication SET Name = ? SET Version = Version + 1 WHERE ISBN = ?"},
+			expectVersion:    3,
+		},
+		{
+			description:      "stale delete",
+			SQL:              "DELETE FROM Publication WHERE ISBN = ?",
+			args:             []interface{}{"AAA", 2},
+			expectStatements: []string{"DELETE FROM Publication WHERE (ISBN = ?) AND Version = ?"},
+			expectVersion:    3,
+			expectErr:        ErrOptimisticLock,
+		},
+		{
+			description:      "delete",
+			SQL:              "DELETE FROM Publication WHERE ISBN = ?",
+			args:             []interface{}{"AAA", 3},
+			expectStatements: []string{"DELETE FROM Publication WHERE (ISBN = ?) AND Version = ?"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		server.Reset()
+		_, err := db.Exec(testCase.SQL, testCase.args...)
+		assert.EqualValues(t, testCase.expectStatements, server.Statements(), testCase.description)
+		assert.EqualValues(t, testCase.expectVersion, version, testCase.description)
+		if testCase.expectErr != nil {
+			assert.True(t, errors.Is(err, testCase.expectErr), testCase.description)
+			continue
+		}
+		assert.Nil(t, err, testCase.description)
+	}
+}
+
+func TestStatement_RowsAffected(t *testing.T) {
+	stored := map[string]bool{"AAA": true, "BBB": true}
+	server := newTestServer(map[string]testHandler{
+		"DescribeTable": staticOutput(publicationTable),
+		"ExecuteStatement": func(input []byte) (string, error) {
+			request := struct {
+				Statement  string
+				Parameters []struct{ S string }
+			}{}
+			_ = json.Unmarshal(input, &request)
+			var items []string
+			if strings.Contains(request.Statement, "RETURNING") {
+				for _, parameter := range request.Parameters {
+					if stored[parameter.S] {
+						items = append(items, fmt.Sprintf(`{"ISBN":{"S":%q}}`, parameter.S))
+					}
+				}
+			}
+			return `{"Items":[` + strings.Join(items, ",") + `]}`, nil
+		},
+	})
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+	keys := []string{"AAA", "BBB"}
+	for i := 0; i < 58; i++ {
+		keys = append(keys, fmt.Sprintf("M-%v", i))
+	}
+
+	var testCases = []struct {
+		description    string
+		SQL            string
+		args           []interface{}
+		expectRequests int
+		expectAffected int64
+	}{
+		{
+			description:    "update counts written item",
+			SQL:            "UPDATE Publication SET Name = ? WHERE ISBN = ?",
+			args:           []interface{}{"Go", "AAA"},
+			expectRequests: 1,
+			expectAffected: 1,
+		},
+		{
+			description:    "delete with returning skips missing item",
+			SQL:            "DELETE FROM Publication WHERE ISBN = ? RETURNING ISBN",
+			args:           []interface{}{"ZZZ"},
+			expectRequests: 1,
+			expectAffected: 0,
+		},
+		{
+			description:    "chunked update with returning counts returned items",
+			SQL:            "UPDATE Publication SET Name = ? WHERE ISBN IN (?) RETURNING ISBN",
+			args:           []interface{}{"Go", keys},
+			expectRequests: 2,
+			expectAffected: 2,
+		},
+		{
+			description:    "empty list",
+			SQL:            "DELETE FROM Publication WHERE ISBN IN (?)",
+			args:           []interface{}{[]string{}},
+			expectRequests: 0,
+			expectAffected: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		server.Reset()
+		result, err := db.Exec(testCase.SQL, testCase.args...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		affected, err := result.RowsAffected()
+		assert.Nil(t, err, testCase.description)
+		assert.EqualValues(t, testCase.expectAffected, affected, testCase.description)
+		assert.EqualValues(t, testCase.expectRequests, server.Calls("ExecuteStatement"), testCase.description)
+	}
+}
@@ -0,0 +1,160 @@
+package dyndb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streams "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	ndynamodb "github.com/viant/dyndb/internal/dynamodb"
+	"github.com/viant/dyndb/internal/exec"
+	"io"
+)
+
+//streamReader reads table change stream shards from checkpoint page by page until shards are caught up
+type streamReader struct {
+	stmt       *Statement
+	args       []driver.NamedValue
+	streamArn  *string
+	shards     []streams.Shard
+	shard      int
+	iterator   *string
+	checkpoint exec.StreamCheckpoint
+}
+
+//streamRows returns rows reading stream records as they are consumed, LIMIT stops reading shards
+func (s *Statement) streamRows(ctx context.Context, state *exec.State, deserializer *ndynamodb.DeserializeMiddleware, args []driver.NamedValue) (*Rows, error) {
+	reader, err := s.newStreamReader(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.loadRows(state, deserializer, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rows.stream = reader
+	rows.ctx = ctx
+	if limit := s.execution.StreamLimit(); limit >= 0 {
+		value := int32(limit)
+		rows.limit = &value
+	}
+	if err = rows.nextStreamPage(); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *Statement) newStreamReader(ctx context.Context, args []driver.NamedValue) (*streamReader, error) {
+	execution := s.execution
+	checkpoint, err := execution.StreamCheckpoint(args)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := s.schemas.describe(ctx, s.client, execution.Table)
+	if err != nil {
+		return nil, err
+	}
+	if desc.LatestStreamArn == nil || desc.StreamSpecification == nil || desc.StreamSpecification.StreamEnabled == nil || !*desc.StreamSpecification.StreamEnabled {
+		return nil, fmt.Errorf("%w: stream is not enabled on table %v", exec.ErrInvalid, execution.Table)
+	}
+	shards, err := s.streamShards(ctx, desc.LatestStreamArn)
+	if err != nil {
+		return nil, err
+	}
+	var shardIDs []string
+	for _, shard := range shards {
+		shardIDs = append(shardIDs, *shard.ShardId)
+	}
+	checkpoint.Retain(shardIDs)
+	return &streamReader{stmt: s, args: args, streamArn: desc.LatestStreamArn, shards: shards, checkpoint: checkpoint}, nil
+}
+
+//next returns rows of the next shard iterator page with matching records, no rows are returned once all shards are caught up
+func (r *streamReader) next(ctx context.Context) ([]map[string]types.AttributeValue, error) {
+	for r.shard < len(r.shards) {
+		shard := r.shards[r.shard]
+		if r.iterator == nil {
+			input := &dynamodbstreams.GetShardIteratorInput{StreamArn: r.streamArn, ShardId: shard.ShardId, ShardIteratorType: streams.ShardIteratorTypeTrimHorizon}
+			if sequenceNumber, ok := r.checkpoint[*shard.ShardId]; ok {
+				input.ShardIteratorType = streams.ShardIteratorTypeAfterSequenceNumber
+				input.SequenceNumber = &sequenceNumber
+			}
+			iterator, err := r.stmt.streams.GetShardIterator(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			if r.iterator = iterator.ShardIterator; r.iterator == nil {
+				r.shard++
+				continue
+			}
+		}
+		output, err := r.stmt.streams.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{ShardIterator: r.iterator})
+		if err != nil {
+			return nil, err
+		}
+		items, err := r.stmt.execution.StreamItems(*shard.ShardId, output.Records, r.checkpoint, r.args)
+		if err != nil {
+			return nil, err
+		}
+		r.iterator = output.NextShardIterator
+		if len(output.Records) == 0 && shard.SequenceNumberRange != nil && shard.SequenceNumberRange.EndingSequenceNumber == nil {
+			r.iterator = nil //open shard is caught up
+		}
+		if r.iterator == nil {
+			r.shard++
+		}
+		if len(items) > 0 {
+			return items, nil
+		}
+	}
+	return nil, nil
+}
+
+//streamShards returns stream shards, parent shards precede their children
+func (s *Statement) streamShards(ctx context.Context, streamArn *string) ([]streams.Shard, error) {
+	if s.streams == nil {
+		return nil, fmt.Errorf("%w: dynamodb streams client was not configured", exec.ErrInvalid)
+	}
+	var shards []streams.Shard
+	input := &dynamodbstreams.DescribeStreamInput{StreamArn: streamArn}
+	for {
+		output, err := s.streams.DescribeStream(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, output.StreamDescription.Shards...)
+		if output.StreamDescription.LastEvaluatedShardId == nil {
+			break
+		}
+		input.ExclusiveStartShardId = output.StreamDescription.LastEvaluatedShardId
+	}
+	return orderShards(shards), nil
+}
+
+//orderShards orders shards so that parent shard records are read before child shard records
+func orderShards(shards []streams.Shard) []streams.Shard {
+	index := make(map[string]streams.Shard, len(shards))
+	for _, shard := range shards {
+		index[*shard.ShardId] = shard
+	}
+	var result []streams.Shard
+	visited := make(map[string]bool, len(shards))
+	var visit func(shard streams.Shard)
+	visit = func(shard streams.Shard) {
+		if visited[*shard.ShardId] {
+			return
+		}
+		visited[*shard.ShardId] = true
+		if shard.ParentShardId != nil {
+			if parent, ok := index[*shard.ParentShardId]; ok {
+				visit(parent)
+			}
+		}
+		result = append(result, shard)
+	}
+	for _, shard := range shards {
+		visit(shard)
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package dyndb
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStatement_StreamRows(t *testing.T) {
+	record := `{"eventID":"%v","eventName":"INSERT","dynamodb":{"Keys":{"Id":{"N":"%v"}},"SequenceNumber":"%v"}}`
+	server := newTestServer(map[string]testHandler{
+		"DescribeTable":    staticOutput(`{"Table":{"TableName":"Users","LatestStreamArn":"arn:stream","StreamSpecification":{"StreamEnabled":true}}}`),
+		"DescribeStream":   staticOutput(`{"StreamDescription":{"Shards":[{"ShardId":"s1","SequenceNumberRange":{"StartingSequenceNumber":"1","EndingSequenceNumber":"3"}}]}}`),
+		"GetShardIterator": staticOutput(`{"ShardIterator":"0"}`),
+		"GetRecords": func(input []byte) (string, error) {
+			request := struct{ ShardIterator string }{}
+			_ = json.Unmarshal(input, &request)
+			if request.ShardIterator == "0" {
+				return `{"Records":[` + fmt.Sprintf(record, 1, 1, 1) + "," + fmt.Sprintf(record, 2, 2, 2) + `],"NextShardIterator":"1"}`, nil
+			}
+			return `{"Records":[` + fmt.Sprintf(record, 3, 3, 3) + `]}`, nil
+		},
+	})
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var testCases = []struct {
+		description string
+		SQL         string
+		expect      []string
+		expectCalls []int
+	}{
+		{
+			description: "records are fetched as rows are read",
+			SQL:         "SELECT EventID FROM STREAM(Users)",
+			expect:      []string{"1", "2", "3"},
+			expectCalls: []int{1, 1, 2},
+		},
+		{
+			description: "limit stops reading",
+			SQL:         "SELECT EventID FROM STREAM(Users) LIMIT 2",
+			expect:      []string{"1", "2"},
+			expectCalls: []int{1, 1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		server.Reset()
+		rows, err := db.Query(testCase.SQL)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []string
+		var calls []int
+		for rows.Next() {
+			var eventID string
+			if assert.Nil(t, rows.Scan(&eventID), testCase.description) {
+				actual = append(actual, eventID)
+				calls = append(calls, server.Calls("GetRecords"))
+			}
+		}
+		assert.Nil(t, rows.Err(), testCase.description)
+		assert.Nil(t, rows.Close(), testCase.description)
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+		assert.EqualValues(t, testCase.expectCalls, calls, testCase.description)
+	}
+}
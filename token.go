@@ -0,0 +1,153 @@
+package dyndb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	ndynamodb "github.com/viant/dyndb/internal/dynamodb"
+	"github.com/viant/dyndb/internal/exec"
+	"hash/fnv"
+	"strconv"
+	"sync"
+)
+
+const (
+	continuationKey = contextKey("continuation")
+	startTokenKey   = contextKey("startToken")
+)
+
+type (
+	//Continuation collects SELECT continuation token, it is updated as rows are read
+	Continuation struct {
+		position pagePosition
+		mux      sync.Mutex
+	}
+
+	//pagePosition represents rows position: request (IN list chunk), DynamoDB token of the page and rows read from that page
+	pagePosition struct {
+		Query   uint32 `json:"q"`
+		Request int    `json:"r,omitempty"`
+		Token   string `json:"t,omitempty"`
+		Skip    int    `json:"s,omitempty"`
+		done    bool
+	}
+)
+
+//WithContinuation returns context collecting continuation token of SELECT executed with it
+func WithContinuation(ctx context.Context, continuation *Continuation) context.Context {
+	return context.WithValue(ctx, continuationKey, continuation)
+}
+
+//WithStartToken returns context starting SELECT from continuation token
+func WithStartToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, startTokenKey, token)
+}
+
+//Token returns token resuming rows after the last read row, or empty string if all rows have been read
+func (c *Continuation) Token() string {
+	c.mux.Lock()
+	position := c.position
+	c.mux.Unlock()
+	return position.encode()
+}
+
+func (c *Continuation) set(position pagePosition) {
+	c.mux.Lock()
+	c.position = position
+	c.mux.Unlock()
+}
+
+func (p *pagePosition) encode() string {
+	if p.done {
+		return ""
+	}
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+//decodePosition decodes continuation token issued for query requests hash
+func decodePosition(token string, hash uint32) (*pagePosition, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid continuation token: %v", exec.ErrInvalid, err)
+	}
+	result := &pagePosition{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("%w: invalid continuation token: %v", exec.ErrInvalid, err)
+	}
+	if result.Query != hash {
+		return nil, fmt.Errorf("%w: continuation token was issued for a different query, arguments or IN list chunks", exec.ErrInvalid)
+	}
+	return result, nil
+}
+
+//startPosition returns position of context start token
+func startPosition(ctx context.Context, hash uint32) (*pagePosition, error) {
+	token, _ := ctx.Value(startTokenKey).(string)
+	if token == "" {
+		return &pagePosition{}, nil
+	}
+	return decodePosition(token, hash)
+}
+
+//queryHash returns hash of query and its requests (IN list chunks) with encoded parameters,
+//token position (request index, DynamoDB token) is only valid for the same requests
+func queryHash(query string, requests []*exec.Request) (uint32, error) {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(query))
+	for _, request := range requests {
+		var item = make(map[string]types.AttributeValue, len(request.Parameters))
+		for i, parameter := range request.Parameters {
+			item[strconv.Itoa(i)] = parameter
+		}
+		data, err := ndynamodb.MarshalItems(item)
+		if err != nil {
+			return 0, err
+		}
+		_, _ = hash.Write([]byte{0})
+		_, _ = hash.Write([]byte(request.Query))
+		_, _ = hash.Write(data)
+	}
+	return hash.Sum32(), nil
+}
+
+//NextToken returns token resuming rows after the last read row with WithStartToken, or empty string if all rows have been read
+func (r *Rows) NextToken() string {
+	position := r.position()
+	return position.encode()
+}
+
+func (r *Rows) position() pagePosition {
+	if r.requests == 0 {
+		return pagePosition{done: true}
+	}
+	result := pagePosition{Query: r.queryHash, Request: r.request}
+	switch {
+	case r.index < len(r.deserializer.Output.Rows):
+		if r.pageToken != nil {
+			result.Token = *r.pageToken
+		}
+		result.Skip = r.index
+	case len(r.sampled) > 0:
+		result.Request = r.sampled[0].request
+		if r.sampled[0].token != nil {
+			result.Token = *r.sampled[0].token
+		}
+	case r.nextToken != nil:
+		result.Token = *r.nextToken
+	case r.request+1 < r.requests:
+		result.Request++
+	default:
+		result.done = true
+	}
+	return result
+}
+
+//updateContinuation updates context continuation with current position
+func (r *Rows) updateContinuation() {
+	if r.continuation != nil {
+		r.continuation.set(r.position())
+	}
+}
@@ -0,0 +1,110 @@
+package dyndb
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestContinuation(t *testing.T) {
+	server := newTestServer(publicationPages())
+	defer server.Close()
+
+	var testCases = []struct {
+		description string
+		options     []string
+		SQL         string
+		pageSize    int
+		prefetch    int
+		expect      [][]string
+	}{
+		{description: "partial pages", SQL: "SELECT ISBN FROM Publication", pageSize: 3, expect: [][]string{{"0-1", "0-2", "1-1"}, {"1-2", "2-1", "2-2"}}},
+		{description: "page boundary", SQL: "SELECT ISBN FROM Publication", pageSize: 2, expect: [][]string{{"0-1", "0-2"}, {"1-1", "1-2"}, {"2-1", "2-2"}}},
+		{description: "prefetch", SQL: "SELECT ISBN FROM Publication", pageSize: 4, prefetch: 2, expect: [][]string{{"0-1", "0-2", "1-1", "1-2"}, {"2-1", "2-2"}}},
+		{description: "sampled wildcard pages", options: []string{"wildcardPages=2"}, SQL: "SELECT * FROM Publication", pageSize: 1, expect: [][]string{{"0-1"}, {"0-2"}, {"1-1"}, {"1-2"}, {"2-1"}, {"2-2"}}},
+		{description: "sampled wildcard pages with prefetch", options: []string{"wildcardPages=2"}, SQL: "SELECT * FROM Publication", pageSize: 3, prefetch: 1, expect: [][]string{{"0-1", "0-2", "1-1"}, {"1-2", "2-1", "2-2"}}},
+	}
+
+	for _, testCase := range testCases {
+		db, err := server.Open(testCase.options...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual [][]string
+		token := ""
+		for i := 0; i < len(testCase.expect)+1; i++ {
+			continuation := &Continuation{}
+			ctx := WithPrefetch(WithContinuation(WithStartToken(context.Background(), token), continuation), testCase.prefetch)
+			rows, err := db.QueryContext(ctx, testCase.SQL)
+			if !assert.Nil(t, err, testCase.description) {
+				break
+			}
+			columns, _ := rows.Columns()
+			var page []string
+			for len(page) < testCase.pageSize && rows.Next() {
+				var ISBN string
+				values := []interface{}{&ISBN}
+				for len(values) < len(columns) {
+					values = append(values, new(interface{}))
+				}
+				if assert.Nil(t, rows.Scan(values...), testCase.description) {
+					page = append(page, ISBN)
+				}
+			}
+			assert.Nil(t, rows.Close(), testCase.description)
+			if len(page) > 0 {
+				actual = append(actual, page)
+			}
+			if token = continuation.Token(); token == "" {
+				break
+			}
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+		assert.Equal(t, "", token, testCase.description)
+	}
+
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = db.QueryContext(WithStartToken(context.Background(), "invalid"), "SELECT ISBN FROM Publication")
+	assert.NotNil(t, err)
+}
+
+func TestContinuation_Arguments(t *testing.T) {
+	server := newTestServer(publicationPages())
+	defer server.Close()
+	db, err := server.Open()
+	if !assert.Nil(t, err) {
+		return
+	}
+	continuation := &Continuation{}
+	rows, err := db.QueryContext(WithContinuation(context.Background(), continuation), "SELECT ISBN FROM Publication WHERE ISBN IN (?)", []string{"0-1", "0-2"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, rows.Next())
+	assert.Nil(t, rows.Close())
+	token := continuation.Token()
+
+	var testCases = []struct {
+		description string
+		args        []interface{}
+		expectErr   bool
+	}{
+		{description: "same arguments", args: []interface{}{[]string{"0-1", "0-2"}}},
+		{description: "different arguments", args: []interface{}{[]string{"0-1", "1-1"}}, expectErr: true},
+		{description: "different IN list chunks", args: []interface{}{[]string{"0-1", "0-2", "1-1"}}, expectErr: true},
+	}
+	for _, testCase := range testCases {
+		rows, err := db.QueryContext(WithStartToken(context.Background(), token), "SELECT ISBN FROM Publication WHERE ISBN IN (?)", testCase.args...)
+		if testCase.expectErr {
+			assert.True(t, errors.Is(err, ErrValidation), testCase.description)
+			continue
+		}
+		if assert.Nil(t, err, testCase.description) {
+			assert.Nil(t, rows.Close(), testCase.description)
+		}
+	}
+}